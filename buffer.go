@@ -15,6 +15,17 @@ type buffer struct {
 	last  States
 	state States
 	class Classes
+
+	// origin is the position of the data in the stream of the Decoder, nil for the other data
+	origin *origin
+	// sequence is true if the record separator of JSON text sequence (RFC 7464) terminates the token
+	sequence bool
+
+	// pending is the index of the incomplete token plus one (zero if there is no such token), scanned is the index, its
+	// scan is continued from, and escape is true if the last scanned byte of the string is the escaping backslash
+	pending int
+	scanned int
+	escape  bool
 }
 
 const __ = -1
@@ -50,6 +61,8 @@ const (
 	ampersand    byte = '&'
 	pipe         byte = '|'
 	question     byte = '?'
+
+	recordSeparator byte = 0x1E
 )
 
 type (
@@ -113,6 +126,46 @@ func (b *buffer) backslash() (result bool) {
 	return
 }
 
+// complete checks if the token, started at the current index, is fully available in the buffer. Single quote is the
+// string delimiter too, for the relaxed mode, as well as the symbols of identifiers are the part of the token.
+//
+// Scan of the incomplete token is continued from the place, where the previous call stopped, so the data, appended
+// by chunks, isn't re-scanned from the start of the token.
+func (b *buffer) complete(relaxed bool) bool {
+	if b.index >= b.length {
+		return false
+	}
+	c := b.data[b.index]
+	str := c == quotes || (relaxed && c == quote)
+	if b.pending != b.index+1 {
+		b.pending = b.index + 1
+		b.scanned = b.index
+		b.escape = false
+		if str {
+			b.scanned++
+		}
+	}
+	for ; b.scanned < b.length; b.scanned++ {
+		current := b.data[b.scanned]
+		if str {
+			switch {
+			case b.escape:
+				b.escape = false
+			case current == backslash:
+				b.escape = true
+			case current == c:
+				return true
+			}
+			continue
+		}
+		if !(current == plus || current == minus || current == dot || (current >= '0' && current <= '9') ||
+			(current >= 'A' && current <= 'Z') || (current >= 'a' && current <= 'z') || (relaxed && identifier(current))) {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *buffer) skip(s byte) error {
 	for ; b.index < b.length; b.index++ {
 		if b.data[b.index] == s && !b.backslash() {
@@ -137,6 +190,9 @@ func (b *buffer) numeric(token bool) error {
 		b.last = GO
 	}
 	for ; b.index < b.length; b.index++ {
+		if b.sequence && b.data[b.index] == recordSeparator {
+			break
+		}
 		b.class = b.getClasses(quotes)
 		if b.class == __ {
 			return b.errorSymbol()
@@ -241,3 +241,29 @@ func TestBuffer_Numeric(t *testing.T) {
 		})
 	}
 }
+
+func TestBuffer_complete(t *testing.T) {
+	buf := newBuffer([]byte(`"abc\`))
+	if buf.complete(false) {
+		t.Errorf("complete() wrong result for the incomplete string")
+	}
+	if buf.scanned != buf.length || !buf.escape {
+		t.Errorf("complete() wrong scan state: %d, %t", buf.scanned, buf.escape)
+	}
+	buf.data = append(buf.data, []byte(`"def"`)...)
+	buf.length = len(buf.data)
+	if !buf.complete(false) || buf.scanned != 9 {
+		t.Errorf("complete() wrong result for the complete string, scanned: %d", buf.scanned)
+	}
+
+	buf = newBuffer([]byte(`[-12`))
+	buf.index = 1
+	if buf.complete(false) || buf.scanned != 4 {
+		t.Errorf("complete() wrong result for the incomplete numeric, scanned: %d", buf.scanned)
+	}
+	buf.data = append(buf.data, []byte(`3]`)...)
+	buf.length = len(buf.data)
+	if !buf.complete(false) || buf.scanned != 5 {
+		t.Errorf("complete() wrong result for the complete numeric, scanned: %d", buf.scanned)
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -16,18 +15,21 @@ var version = "v0.6.0"
 
 func usage() {
 	text := ``
-	if inArgs("-h", "-help", "--help", "help") || len(os.Args) > 3 {
-		text = `Usage: ajson "jsonpath" ["input"]
+	if inArgs("-h", "-help", "--help", "help") || len(arguments()) > 3 {
+		text = `Usage: ajson [-n] "jsonpath" ["input"]
   Read JSON and evaluate it with JSONPath.
 Argument:
   jsonpath   Valid JSONPath or evaluate string (Examples: "$..[?(@.price)]", "$..price", "avg($..price)")
   input      Path to the JSON file. Leave it blank to use STDIN.
+Options:
+  -n, --ndjson  Read newline-delimited JSON (JSON Lines), evaluate each record and print results line by line.
 Examples:
   ajson "avg($..registered.age)" "https://randomuser.me/api/?results=5000"
   ajson "$.results.*.name" "https://randomuser.me/api/?results=10"
   curl -s "https://randomuser.me/api/?results=10" | ajson "$..coordinates"
   ajson "$" example.json
-  echo "3" | ajson "2 * pi * $"`
+  echo "3" | ajson "2 * pi * $"
+  printf '{"a":1}\n{"a":2}\n' | ajson -n "$.a"`
 	} else if inArgs("version", "-version", "--version") {
 		text = fmt.Sprintf(`ajson: Version %s
 Copyright (c) 2020 Pyzhov Stepan
@@ -44,49 +46,88 @@ There is NO WARRANTY, to the extent permitted by law.`, version)
 func main() {
 	log.SetFlags(0)
 	usage()
-	if len(os.Args) < 2 {
+	args := arguments()
+	if len(args) < 2 {
 		log.Fatalf("JSONPath was not set")
 	}
-	path := os.Args[1]
-	input := getInput()
+	path := args[1]
+	input := getInput(args)
 	defer func() {
 		_ = input.Close()
 	}()
-	data, err := ioutil.ReadAll(input)
 
+	if inArgs("-n", "--ndjson") {
+		lines(input, path)
+		return
+	}
+
+	decoder := ajson.NewDecoder(input)
+	root, err := decoder.Decode()
+	if err == io.EOF {
+		log.Fatalf("error parsing JSON: empty input")
+	}
 	if err != nil {
-		log.Fatalf("error reading source: %s", err)
+		log.Fatalf("error parsing JSON: %s", describe(err))
+	}
+	// only the single document is allowed, like for the ajson.Unmarshal
+	if _, err = decoder.Decode(); err == nil {
+		log.Fatalf("error parsing JSON: unexpected value after the document")
+	} else if err != io.EOF {
+		log.Fatalf("error parsing JSON: %s", describe(err))
 	}
-	var result *ajson.Node
 
-	root, err := ajson.Unmarshal(data)
+	data, err := ajson.Marshal(evaluate(root, path))
 	if err != nil {
-		log.Fatalf("error parsing JSON: %s", err)
+		log.Fatalf("error preparing JSON: %s", err)
 	}
+	fmt.Printf("%s\n", data)
+}
 
-	var nodes []*ajson.Node
-	nodes, err = root.JSONPath(path)
-	result = ajson.ArrayNode("", nodes)
+func lines(input io.Reader, path string) {
+	reader := ajson.NewLinesReader(input)
+	writer := ajson.NewLinesWriter(os.Stdout)
+	for {
+		root, line, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("error parsing JSON at line %d: %s", line, describe(err))
+		}
+		err = writer.Write(evaluate(root, path))
+		if err != nil {
+			log.Fatalf("error preparing JSON at line %d: %s", line, err)
+		}
+	}
+}
+
+func evaluate(root *ajson.Node, path string) *ajson.Node {
+	nodes, err := root.JSONPath(path)
+	result := ajson.ArrayNode("", nodes)
 	if err != nil {
 		result, err = ajson.Eval(root, path)
 	}
 	if err != nil {
-		log.Fatalf("error: %s", err)
+		log.Fatalf("error: %s", describe(err))
 	}
+	return result
+}
 
-	data, err = ajson.Marshal(result)
-	if err != nil {
-		log.Fatalf("error preparing JSON: %s", err)
+// describe returns the error message with the position and excerpt of the source, if they are known
+func describe(err error) string {
+	current, ok := err.(ajson.Error)
+	if !ok || current.Line == 0 {
+		return err.Error()
 	}
-	fmt.Printf("%s\n", data)
+	return fmt.Sprintf("%s (line %d, column %d)\n%s", current, current.Line, current.Column, current.Excerpt)
 }
 
-func getInput() io.ReadCloser {
-	if len(os.Args) < 3 {
+func getInput(args []string) io.ReadCloser {
+	if len(args) < 3 {
 		return os.Stdin
 	}
 
-	input := os.Args[2]
+	input := args[2]
 	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
 		resp, err := http.DefaultClient.Get(input)
 		if err != nil {
@@ -104,6 +145,18 @@ func getInput() io.ReadCloser {
 	return file
 }
 
+// arguments returns command line arguments without options
+func arguments() []string {
+	result := make([]string, 0, len(os.Args))
+	for _, arg := range os.Args {
+		if arg == "-n" || arg == "--ndjson" {
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
 func inArgs(value ...string) bool {
 	index := make(map[string]bool, len(value))
 	for _, val := range value {
@@ -1,6 +1,8 @@
 package ajson
 
 import (
+	"io"
+
 	. "github.com/spyzhov/ajson/internal"
 )
 
@@ -23,59 +25,266 @@ const (
 //
 // Doesn't calculate values, just type of stored value. It will store link to the data, on all life long.
 func Unmarshal(data []byte) (root *Node, err error) {
-	buf := newBuffer(data)
-	var (
-		state   States
-		key     *string
-		current *Node
-	)
+	return UnmarshalWithOptions(data, Options{})
+}
 
-	_, err = buf.first()
+// UnmarshalWithOptions parses the JSON-encoded data with the given options and return the root node of struct.
+func UnmarshalWithOptions(data []byte, options Options) (root *Node, err error) {
+	p := newParser(data)
+	p.options = options
+	root, err = p.parse(true)
 	if err != nil {
-		return nil, buf.errorEOF()
+		return nil, locate(err, data)
+	}
+	err = p.first(true)
+	if err == nil {
+		return nil, locate(p.buf.errorSymbol(), data)
+	}
+	if err != io.EOF {
+		return nil, locate(err, data)
+	}
+	return root, nil
+}
+
+// UnmarshalSequence parses the data, that contains several JSON values, and returns root nodes of all of them in the
+// same order, as they were found, with their offsets in the data.
+//
+// Values can be concatenated (`{"a":1}{"b":2}`), divided by whitespaces, or represented as JSON text sequence
+// (RFC 7464), where each value is prefixed by the record separator (0x1E).
+func UnmarshalSequence(data []byte) (result []*Node, offsets []int, err error) {
+	var root *Node
+	p := newParser(data)
+	p.buf.sequence = true
+	result = make([]*Node, 0)
+	offsets = make([]int, 0)
+	for {
+		if _, err = p.buf.first(); err != nil {
+			return result, offsets, nil
+		}
+		if p.buf.data[p.buf.index] == recordSeparator {
+			p.buf.index++
+			continue
+		}
+		p.reset()
+		root, err = p.parse(true)
+		if err != nil {
+			return nil, nil, locate(err, data)
+		}
+		result = append(result, root)
+		offsets = append(offsets, root.borders[0])
+	}
+}
+
+// parser is a resumable state of the parsing loop: it keeps the position in the buffer, the last found key and the
+// current node, so parsing can be continued after new data was appended to the buffer.
+type parser struct {
+	buf      *buffer
+	key      *string
+	keyIndex int
+	current  *Node
+	options  Options
+	nodes    int
+	// members is the count of members of the objects, with the duplicated keys, for Options.MaxObjectKeys
+	members map[*Node]int
+	// stream is set for the incremental parsing, where the string cut by the end of data is an UnexpectedEOF error
+	stream bool
+}
+
+func newParser(data []byte) *parser {
+	return &parser{
+		buf: newBuffer(data),
+	}
+}
+
+// reset prepares parser to find the next root node
+func (p *parser) reset() {
+	p.key = nil
+	p.current = nil
+	p.nodes = 0
+	p.members = nil
+	p.buf.last = GO
+	p.buf.state = GO
+}
+
+// newNode creates the next node at the current position, with the respect of the limits
+func (p *parser) newNode(_type NodeType) (*Node, error) {
+	if p.options.MaxNodes > 0 {
+		if p.nodes >= p.options.MaxNodes {
+			return nil, errorLimit(p.buf.index, "MaxNodes", p.options.MaxNodes)
+		}
+		p.nodes++
+	}
+	if p.options.MaxDepth > 0 && (_type == Array || _type == Object) {
+		depth := 1
+		for node := p.current; node != nil; node = node.parent {
+			depth++
+		}
+		if depth > p.options.MaxDepth {
+			return nil, errorLimit(p.buf.index, "MaxDepth", p.options.MaxDepth)
+		}
+	}
+	if p.options.MaxObjectKeys > 0 && p.current.IsObject() {
+		if p.members == nil {
+			p.members = make(map[*Node]int)
+		}
+		if p.members[p.current] >= p.options.MaxObjectKeys {
+			return nil, errorLimit(p.buf.index, "MaxObjectKeys", p.options.MaxObjectKeys)
+		}
+		p.members[p.current]++
+	}
+	if p.current.IsObject() && p.key != nil {
+		if previous, ok := p.current.children[*p.key]; ok {
+			return p.duplicate(previous, _type)
+		}
 	}
+	return newNode(p.current, p.buf, _type, &p.key)
+}
+
+// duplicate creates the node for the duplicated key, with the respect of Options.DuplicateKeys policy. Object, which
+// value was dropped, is marked as dirty, so Marshal won't return the dropped value, that could be read by others.
+func (p *parser) duplicate(previous *Node, _type NodeType) (current *Node, err error) {
+	switch p.options.DuplicateKeys {
+	case DuplicateKeysError:
+		return nil, errorDuplicate(p.keyIndex, *p.key)
+	case DuplicateKeysFirst:
+		// node is parsed as usual, but it will be unreachable from the parent
+		current, err = newNode(p.current, p.buf, _type, &p.key)
+		p.current.children[*previous.key] = previous
+		p.current.mark()
+		return current, err
+	case DuplicateKeysAll:
+		parent := p.current
+		if parent.duplicates == nil {
+			parent.duplicates = make(map[string][]*Node)
+		}
+		parent.duplicates[*previous.key] = append(parent.duplicates[*previous.key], previous)
+	default:
+		p.current.mark()
+	}
+	// previous value is replaced, so it mustn't change the object anymore
+	previous.parent = nil
+	return newNode(p.current, p.buf, _type, &p.key)
+}
+
+// limitString checks the length of the string (in bytes of the source), started at the given index
+func (p *parser) limitString(length int, index int) error {
+	if p.options.MaxStringLength > 0 && length > p.options.MaxStringLength {
+		return errorLimit(index, "MaxStringLength", p.options.MaxStringLength)
+	}
+	return nil
+}
+
+// limitPending checks the incomplete string token, so the parser won't wait for the rest of the string, that is
+// already too long
+func (p *parser) limitPending() error {
+	if c := p.buf.data[p.buf.index]; c == quotes || c == quote {
+		return p.limitString(p.buf.length-p.buf.index-1, p.buf.index)
+	}
+	return nil
+}
+
+// first skips whitespaces (and comments, for the relaxed mode) before the next token. It returns io.EOF, if the data is
+// over.
+func (p *parser) first(final bool) (err error) {
+	if p.options.Relaxed {
+		return p.skip(final)
+	}
+	_, err = p.buf.first()
+	return err
+}
+
+// parse continues parsing from the current position of the buffer and returns the root node as soon as it's complete.
+// The buffer index will point to the first byte after the root node.
+//
+// If data ends before the root node is complete, it returns nil without error, so parsing could be continued with more
+// data, or UnexpectedEOF error if final is true.
+func (p *parser) parse(final bool) (root *Node, err error) {
+	var (
+		buf            = p.buf
+		state          States
+		last, previous States
+	)
 
 	for {
+		if err = p.first(final); err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			if final {
+				return nil, buf.errorEOF()
+			}
+			return nil, nil
+		}
+
+		if p.options.Relaxed {
+			if !final && !buf.complete(p.options.Relaxed) {
+				return nil, p.limitPending()
+			}
+			if ok, err := p.relaxed(); err != nil {
+				return nil, err
+			} else if ok {
+				buf.index++
+				if p.current.parent == nil && p.current.ready() {
+					return p.current, nil
+				}
+				continue
+			}
+		}
+
+		last, previous = buf.last, buf.state
 		state = buf.getState()
 		if state == __ {
 			return nil, buf.errorSymbol()
 		}
 
 		if state >= GO {
+			if !final && !buf.complete(p.options.Relaxed) {
+				// wait for the rest of the token
+				buf.last, buf.state = last, previous
+				return nil, p.limitPending()
+			}
 			// region Change State
 			switch buf.state {
 			case ST:
-				if current != nil && current.IsObject() && key == nil {
+				if p.current != nil && p.current.IsObject() && p.key == nil {
 					// Detected: Key
-					key, err = getString(buf)
+					p.keyIndex = buf.index
+					p.key, err = getString(buf)
+					err = p.unterminated(err)
+					if err == nil {
+						err = p.limitString(buf.index-p.keyIndex-1, p.keyIndex)
+					}
 					buf.state = CO
 				} else {
 					// Detected: String
-					current, err = newNode(current, buf, String, &key)
+					p.current, err = p.newNode(String)
 					if err != nil {
 						break
 					}
-					err = buf.string(quotes, false)
-					current.borders[1] = buf.index + 1
+					err = p.unterminated(buf.string(quotes, false))
+					p.current.borders[1] = buf.index + 1
+					if err == nil {
+						err = p.limitString(buf.index-p.current.borders[0]-1, p.current.borders[0])
+					}
 					buf.state = OK
-					if current.parent != nil {
-						current = current.parent
+					if p.current.parent != nil {
+						p.current = p.current.parent
 					}
 				}
 			case MI, ZE, IN:
-				current, err = newNode(current, buf, Numeric, &key)
+				p.current, err = p.newNode(Numeric)
 				if err != nil {
 					break
 				}
 				err = buf.numeric(false)
-				current.borders[1] = buf.index
+				p.current.borders[1] = buf.index
 				buf.index -= 1
 				buf.state = OK
-				if current.parent != nil {
-					current = current.parent
+				if p.current.parent != nil {
+					p.current = p.current.parent
 				}
 			case T1, F1:
-				current, err = newNode(current, buf, Bool, &key)
+				p.current, err = p.newNode(Bool)
 				if err != nil {
 					break
 				}
@@ -84,21 +293,21 @@ func Unmarshal(data []byte) (root *Node, err error) {
 				} else {
 					err = buf.false()
 				}
-				current.borders[1] = buf.index + 1
+				p.current.borders[1] = buf.index + 1
 				buf.state = OK
-				if current.parent != nil {
-					current = current.parent
+				if p.current.parent != nil {
+					p.current = p.current.parent
 				}
 			case N1:
-				current, err = newNode(current, buf, Null, &key)
+				p.current, err = p.newNode(Null)
 				if err != nil {
 					break
 				}
 				err = buf.null()
-				current.borders[1] = buf.index + 1
+				p.current.borders[1] = buf.index + 1
 				buf.state = OK
-				if current.parent != nil {
-					current = current.parent
+				if p.current.parent != nil {
+					p.current = p.current.parent
 				}
 			}
 			// endregion Change State
@@ -106,49 +315,49 @@ func Unmarshal(data []byte) (root *Node, err error) {
 			// region Action
 			switch state {
 			case ec: /* empty } */
-				if key != nil {
+				if p.key != nil {
 					err = buf.errorSymbol()
 				}
 				fallthrough
 			case cc: /* } */
-				if current != nil && current.IsObject() && !current.ready() {
-					current.borders[1] = buf.index + 1
-					if current.parent != nil {
-						current = current.parent
+				if p.current != nil && p.current.IsObject() && !p.current.ready() {
+					p.current.borders[1] = buf.index + 1
+					if p.current.parent != nil {
+						p.current = p.current.parent
 					}
 				} else {
 					err = buf.errorSymbol()
 				}
 				buf.state = OK
 			case bc: /* ] */
-				if current != nil && current.IsArray() && !current.ready() {
-					current.borders[1] = buf.index + 1
-					if current.parent != nil {
-						current = current.parent
+				if p.current != nil && p.current.IsArray() && !p.current.ready() {
+					p.current.borders[1] = buf.index + 1
+					if p.current.parent != nil {
+						p.current = p.current.parent
 					}
 				} else {
 					err = buf.errorSymbol()
 				}
 				buf.state = OK
 			case co: /* { */
-				current, err = newNode(current, buf, Object, &key)
+				p.current, err = p.newNode(Object)
 				buf.state = OB
 			case bo: /* [ */
-				current, err = newNode(current, buf, Array, &key)
+				p.current, err = p.newNode(Array)
 				buf.state = AR
 			case cm: /* , */
-				if current == nil {
+				if p.current == nil {
 					return nil, buf.errorSymbol()
 				}
-				if current.IsObject() {
+				if p.current.IsObject() {
 					buf.state = KE
-				} else if current.IsArray() {
+				} else if p.current.IsArray() {
 					buf.state = VA
 				} else {
 					err = buf.errorSymbol()
 				}
 			case cl: /* : */
-				if current == nil || !current.IsObject() || key == nil {
+				if p.current == nil || !p.current.IsObject() || p.key == nil {
 					err = buf.errorSymbol()
 				} else {
 					buf.state = VA
@@ -159,28 +368,21 @@ func Unmarshal(data []byte) (root *Node, err error) {
 			// endregion Action
 		}
 		if err != nil {
-			return
+			return nil, err
 		}
-		if buf.step() != nil {
-			break
-		}
-		if _, err = buf.first(); err != nil {
-			err = nil
-			break
+		buf.index++
+		if p.current != nil && p.current.parent == nil && p.current.ready() {
+			return p.current, nil
 		}
 	}
+}
 
-	if current == nil || buf.state != OK {
-		err = buf.errorEOF()
-	} else {
-		root = current.root()
-		if !root.ready() {
-			err = buf.errorEOF()
-			root = nil
-		}
+// unterminated replaces the error of the string, cut by the end of data, with UnexpectedEOF for the incremental parsing
+func (p *parser) unterminated(err error) error {
+	if err != nil && p.stream && p.buf.index >= p.buf.length {
+		return p.buf.errorEOF()
 	}
-
-	return
+	return err
 }
 
 // UnmarshalSafe do the same thing as Unmarshal, but copy data to the local variable, to make it editable.
@@ -2893,3 +2893,64 @@ line"]`)},
 		})
 	}
 }
+
+func TestUnmarshalSequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		values  []string
+		offsets []int
+		wantErr bool
+	}{
+		{name: "empty", input: ``, values: []string{}, offsets: []int{}},
+		{name: "single", input: `{"a":1}`, values: []string{`{"a":1}`}, offsets: []int{0}},
+		{name: "concatenated", input: `{"a":1}{"b":2}[3]"4"`, values: []string{`{"a":1}`, `{"b":2}`, `[3]`, `"4"`}, offsets: []int{0, 7, 14, 17}},
+		{name: "whitespaces", input: " 1 2\n\ttrue null ", values: []string{`1`, `2`, `true`, `null`}, offsets: []int{1, 3, 6, 11}},
+		{name: "RFC 7464", input: "\x1e{\"a\":1}\n\x1e2\n\x1e\x1e\"3\"\n", values: []string{`{"a":1}`, `2`, `"3"`}, offsets: []int{1, 10, 14}},
+		{name: "wrong value", input: `{"a":1}{"b":}`, wantErr: true},
+		{name: "unclosed value", input: `{"a":1}[1,2`, wantErr: true},
+		{name: "numbers", input: `12`, values: []string{`12`}, offsets: []int{0}},
+		{name: "RFC 7464 numbers", input: "\x1e1\x1e2", values: []string{`1`, `2`}, offsets: []int{1, 3}},
+		{name: "RFC 7464 trailing separator", input: "1\x1e", values: []string{`1`}, offsets: []int{0}},
+		{name: "RFC 7464 literals", input: "\x1e-1.5e3\x1etrue\x1enull", values: []string{`-1.5e3`, `true`, `null`}, offsets: []int{1, 8, 13}},
+		{name: "separator in numeric", input: "\x1e1.\x1e2", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, offsets, err := UnmarshalSequence([]byte(test.input))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("UnmarshalSequence() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				if result != nil || offsets != nil {
+					t.Errorf("UnmarshalSequence() result is not empty")
+				}
+				return
+			}
+			values := make([]string, 0, len(result))
+			for _, node := range result {
+				values = append(values, string(node.Source()))
+			}
+			if !sliceEqual(values, test.values) {
+				t.Errorf("UnmarshalSequence() wrong values: %s, expected: %s", sliceString(values), sliceString(test.values))
+			}
+			if !reflect.DeepEqual(offsets, test.offsets) {
+				t.Errorf("UnmarshalSequence() wrong offsets: %v, expected: %v", offsets, test.offsets)
+			}
+		})
+	}
+}
+
+func ExampleUnmarshalSequence() {
+	data := []byte("\x1e{\"id\":1}\n\x1e{\"id\":2}\n")
+	nodes, offsets, err := UnmarshalSequence(data)
+	if err != nil {
+		panic(err)
+	}
+	for i, node := range nodes {
+		fmt.Printf("%d: %s\n", offsets[i], node)
+	}
+	// Output:
+	// 1: {"id":1}
+	// 11: {"id":2}
+}
@@ -0,0 +1,144 @@
+package ajson
+
+import (
+	"io"
+)
+
+// decoderChunkSize is a minimal size of the data, requested from the reader at once
+const decoderChunkSize = 32 * 1024
+
+// Decoder reads and decodes JSON values from an input stream.
+//
+// Decoder reads the data from the reader chunk by chunk, while the current value is not complete, so there is no need
+// to load the whole input into the memory before parsing. Each decoded node keeps a link to its own part of the data,
+// so Node.Source works the same way as for the result of Unmarshal.
+//
+// Index, Line and Column of the parsing errors, as well as Node.Position of the decoded nodes, are counted from the
+// beginning of the stream.
+type Decoder struct {
+	reader  io.Reader
+	parser  *parser
+	options Options
+	err     error
+	// origin is the position of the current parser buffer in the stream
+	origin origin
+}
+
+// origin is the position of the beginning of the parsed data in the stream: offset, count of lines before it and the
+// count of characters before it on its first line
+type origin struct {
+	offset int
+	line   int
+	column int
+}
+
+// position converts the position in the parsed data into the position in the stream
+func (o *origin) position(offset, line, column int) (int, int, int) {
+	if o == nil {
+		return offset, line, column
+	}
+	if line == 1 {
+		column += o.column
+	}
+	return offset + o.offset, line + o.line, column
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWithOptions(r, Options{})
+}
+
+// NewDecoderWithOptions returns a new decoder that reads from r and parses values with the given options.
+func NewDecoderWithOptions(r io.Reader, options Options) *Decoder {
+	decoder := &Decoder{
+		reader:  r,
+		options: options,
+	}
+	decoder.parser = decoder.newParser(make([]byte, 0, decoderChunkSize))
+	return decoder
+}
+
+// Decode reads the next JSON-encoded value from its input and returns its root node.
+//
+// Input may contain several values, divided by whitespaces, they will be returned one by one. When there are no more
+// values, Decode returns io.EOF error.
+func (d *Decoder) Decode() (root *Node, err error) {
+	for {
+		if d.err != nil {
+			if d.err != io.EOF {
+				return nil, d.err
+			}
+			if d.parser.current == nil {
+				if err = d.parser.first(true); err != nil {
+					return nil, d.locate(err)
+				}
+			}
+		}
+		root, err = d.parser.parse(d.err != nil)
+		if err != nil {
+			return nil, d.locate(err)
+		}
+		if root != nil {
+			d.move()
+			d.parser = d.newParser(d.rest())
+			return root, nil
+		}
+		d.err = d.fill()
+	}
+}
+
+func (d *Decoder) newParser(data []byte) *parser {
+	p := newParser(data)
+	p.options = d.options
+	p.stream = true
+	origin := d.origin
+	p.buf.origin = &origin
+	return p
+}
+
+// fill reads the next chunk of the data from the reader into the buffer. Buffer is reallocated only if there is not
+// enough free space, with the copy of the current value only, so the nodes of the previous values keep only the chunks
+// of the data they were parsed from.
+func (d *Decoder) fill() error {
+	buf := d.parser.buf
+	size := len(buf.data)
+	if cap(buf.data)-size < decoderChunkSize {
+		data := make([]byte, size, 2*size+decoderChunkSize)
+		copy(data, buf.data)
+		buf.data = data
+	}
+	n, err := d.reader.Read(buf.data[size:cap(buf.data)])
+	buf.data = buf.data[:size+n]
+	buf.length = len(buf.data)
+	return err
+}
+
+// rest returns the data that wasn't parsed yet. It shares the memory with the current buffer: the next values are
+// read into the free space after it, which is never used by the nodes of the current value.
+func (d *Decoder) rest() []byte {
+	buf := d.parser.buf
+	return buf.data[buf.index:]
+}
+
+// move shifts the position of the buffer in the stream to the current index
+func (d *Decoder) move() {
+	buf := d.parser.buf
+	consumed := buf.data[:buf.index]
+	line, column, _ := position(consumed, len(consumed))
+	if line > 1 {
+		d.origin.column = 0
+	}
+	d.origin.offset += buf.index
+	d.origin.line += line - 1
+	d.origin.column += column - 1
+}
+
+// locate sets the position of the parsing error in the stream
+func (d *Decoder) locate(err error) error {
+	err = locate(err, d.parser.buf.data)
+	if current, ok := err.(Error); ok && current.Line > 0 {
+		current.Index, current.Line, current.Column = d.origin.position(current.Index, current.Line, current.Column)
+		return current
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package ajson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoder_Decode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty", input: "", want: []string{}},
+		{name: "spaces", input: " \n\t ", want: []string{}},
+		{name: "object", input: `{"foo":"bar"}`, want: []string{`{"foo":"bar"}`}},
+		{name: "numeric", input: `123`, want: []string{`123`}},
+		{name: "numeric with spaces", input: ` 123 `, want: []string{`123`}},
+		{name: "string", input: `"foo \"bar\""`, want: []string{`"foo \"bar\""`}},
+		{name: "several", input: "1 2\n[3] {\"4\":4}\"5\"null", want: []string{`1`, `2`, `[3]`, `{"4":4}`, `"5"`, `null`}},
+		{name: "example", input: string(jsonExample), want: []string{string(jsonExample)}},
+	}
+	readers := map[string]func(io.Reader) io.Reader{
+		"full":     func(r io.Reader) io.Reader { return r },
+		"one byte": iotest.OneByteReader,
+		"half":     iotest.HalfReader,
+	}
+	for _, test := range tests {
+		for name, reader := range readers {
+			t.Run(test.name+"/"+name, func(t *testing.T) {
+				decoder := NewDecoder(reader(strings.NewReader(test.input)))
+				result := make([]string, 0)
+				for {
+					root, err := decoder.Decode()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("Decode() unexpected error: %s", err)
+					}
+					result = append(result, string(root.Source()))
+				}
+				if !sliceEqual(result, test.want) {
+					t.Errorf("Decode() wrong result: %s, expected: %s", sliceString(result), sliceString(test.want))
+				}
+			})
+		}
+	}
+}
+
+func TestDecoder_Decode_error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "unclosed object", input: `{"foo":"bar"`},
+		{name: "unclosed string", input: `"foo`},
+		{name: "wrong symbol", input: `[1,]`},
+		{name: "wrong numeric", input: `1e`},
+		{name: "wrong literal", input: `trux`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewDecoder(iotest.OneByteReader(strings.NewReader(test.input))).Decode()
+			if err == nil || err == io.EOF {
+				t.Errorf("Decode() expected error, got: %v", err)
+			}
+		})
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestDecoder_Decode_reader(t *testing.T) {
+	expected := errors.New("reader error")
+	_, err := NewDecoder(iotest.TimeoutReader(strings.NewReader(`[1, 2, 3`))).Decode()
+	if err != iotest.ErrTimeout {
+		t.Errorf("Decode() expected timeout error, got: %v", err)
+	}
+	_, err = NewDecoder(io.MultiReader(strings.NewReader(`{`), &errorReader{err: expected})).Decode()
+	if err != expected {
+		t.Errorf("Decode() expected reader error, got: %v", err)
+	}
+}
+
+func TestDecoder_Decode_equal(t *testing.T) {
+	expected := Must(Unmarshal(jsonExample))
+	root, err := NewDecoder(iotest.OneByteReader(bytes.NewReader(jsonExample))).Decode()
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if ok, err := root.Eq(expected); err != nil || !ok {
+		t.Errorf("Decode() result is not equal to Unmarshal()")
+	}
+	nodes, err := root.JSONPath("$..price")
+	if err != nil {
+		t.Fatalf("JSONPath() unexpected error: %s", err)
+	}
+	if len(nodes) != 5 || string(nodes[0].Source()) != "19.95" {
+		t.Errorf("JSONPath() wrong result: %s", Paths(nodes))
+	}
+}
+
+func TestDecoder_Decode_position(t *testing.T) {
+	decoder := NewDecoder(iotest.OneByteReader(strings.NewReader("{\"a\": 1}\n  [1,\n 2] \"ключ\" 3")))
+	expected := []Position{
+		{Offset: 11, End: 18, Line: 2, Column: 3, EndLine: 3, EndColumn: 4},
+		{Offset: 19, End: 29, Line: 3, Column: 5, EndLine: 3, EndColumn: 11},
+		{Offset: 30, End: 31, Line: 3, Column: 12, EndLine: 3, EndColumn: 13},
+	}
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	for i, position := range expected {
+		root, err := decoder.Decode()
+		if err != nil {
+			t.Fatalf("Decode() unexpected error: %s", err)
+		}
+		if result, err := root.Position(); err != nil || result != position {
+			t.Errorf("Position() wrong result of value #%d: %+v, expected: %+v", i+1, result, position)
+		}
+		if i == 0 {
+			if result, err := root.MustIndex(1).Position(); err != nil || result.Line != 3 || result.Column != 2 {
+				t.Errorf("Position() wrong result of the element: %+v", result)
+			}
+		}
+	}
+}
+
+func TestDecoder_Decode_allocations(t *testing.T) {
+	input := bytes.Repeat([]byte(`{"a":1}`+"\n"), 10000)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	decoder := NewDecoder(bytes.NewReader(input))
+	nodes := make([]*Node, 0, 10000)
+	for {
+		root, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode() unexpected error: %s", err)
+		}
+		nodes = append(nodes, root)
+	}
+	runtime.ReadMemStats(&after)
+	if len(nodes) != 10000 || string(nodes[9999].Source()) != `{"a":1}` {
+		t.Fatalf("Decode() wrong result: %d values", len(nodes))
+	}
+	// each value must not allocate the chunk of its own
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(nodes)*decoderChunkSize/16) {
+		t.Errorf("Decode() allocated too much: %d bytes", allocated)
+	}
+}
+
+func BenchmarkDecoder_Decode(b *testing.B) {
+	input := bytes.Repeat([]byte(`{"a":1}`+"\n"), 10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		decoder := NewDecoder(bytes.NewReader(input))
+		for {
+			if _, err := decoder.Decode(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func ExampleDecoder() {
+	decoder := NewDecoder(strings.NewReader(`{"id": 1, "name": "foo"} {"id": 2, "name": "bar"}`))
+	for {
+		root, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%s: %s\n", root.MustKey("id").Source(), root.MustKey("name").MustString())
+	}
+	// Output:
+	// 1: foo
+	// 2: bar
+}
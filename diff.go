@@ -0,0 +1,238 @@
+package ajson
+
+import (
+	"strconv"
+)
+
+// Diff returns JSON Patch (RFC 6902), that transforms the node a into the node b: after ApplyPatch(a, Diff(a, b)) the
+// node a is equal to b. Nodes are compared by Node.Eq, so the order of object keys is not the difference.
+//
+// Objects are compared key by key and arrays element by element, so the patch contains only the changed values. Nil
+// node is treated as null.
+func Diff(a, b *Node) *Node {
+	return DiffWithOptions(a, b, DiffOptions{})
+}
+
+// DiffWithOptions returns JSON Patch, that transforms the node a into the node b, with the given options, see
+// DiffOptions.Moves.
+func DiffWithOptions(a, b *Node, options DiffOptions) *Node {
+	if a == nil {
+		a = NullNode("")
+	}
+	if b == nil {
+		b = NullNode("")
+	}
+	d := &differ{options: options, patch: make([]*Node, 0)}
+	d.diff("", a, b)
+	return ArrayNode("", d.patch)
+}
+
+// differ collects the operations of the patch
+type differ struct {
+	options DiffOptions
+	patch   []*Node
+}
+
+// diff appends the operations, that transform a into b, by the pointer of a
+func (d *differ) diff(pointer string, a, b *Node) {
+	if equal(a, b) {
+		return
+	}
+	switch {
+	case a.IsObject() && b.IsObject():
+		for _, key := range a.Keys() {
+			if !b.HasKey(key) {
+				d.operation("remove", pointer+"/"+escapePointer(key), "", nil)
+			}
+		}
+		for _, key := range b.Keys() {
+			if a.HasKey(key) {
+				d.diff(pointer+"/"+escapePointer(key), a.children[key], b.children[key])
+			} else {
+				d.operation("add", pointer+"/"+escapePointer(key), "", b.children[key])
+			}
+		}
+	case a.IsArray() && b.IsArray():
+		d.diffArray(pointer, a.Inheritors(), b.Inheritors())
+	default:
+		d.operation("replace", pointer, "", b)
+	}
+}
+
+// diffArray appends the operations, that transform the array a into b. Elements of the longest common subsequence are
+// kept, the rest are paired: equal elements are moved (with DiffOptions.Moves), elements between the same kept ones
+// are changed in place, unpaired elements are removed and added.
+func (d *differ) diffArray(pointer string, a, b []*Node) {
+	const (
+		deleted = -1
+		added   = -1
+	)
+	source := make([]int, len(b)) // index of the element of a for each element of b, or added
+	target := make([]int, len(a)) // index of the element of b for each element of a, or deleted
+	for i := range source {
+		source[i] = added
+	}
+	for i := range target {
+		target[i] = deleted
+	}
+	kept := make([]bool, len(b))
+	common := commonSubsequence(a, b)
+	for _, pair := range common {
+		source[pair[1]], target[pair[0]], kept[pair[1]] = pair[0], pair[1], true
+	}
+	moved := make([]bool, len(b))
+	if d.options.Moves {
+		for j := range b {
+			if source[j] != added {
+				continue
+			}
+			for i := range a {
+				if target[i] == deleted && equal(a[i], b[j]) {
+					source[j], target[i], moved[j] = i, j, true
+					break
+				}
+			}
+		}
+	}
+	// the rest elements between the same kept elements are paired in order
+	i, j := 0, 0
+	for _, pair := range append(common, [2]int{len(a), len(b)}) {
+		for {
+			for i < pair[0] && target[i] != deleted {
+				i++
+			}
+			for j < pair[1] && source[j] != added {
+				j++
+			}
+			if i == pair[0] || j == pair[1] {
+				break
+			}
+			source[j], target[i] = i, j
+		}
+		i, j = pair[0]+1, pair[1]+1
+	}
+
+	// current is the order of the elements of a, while the operations are applied
+	current := make([]int, 0, len(a))
+	for i := len(a) - 1; i >= 0; i-- {
+		if target[i] == deleted {
+			d.operation("remove", pointer+"/"+strconv.Itoa(i), "", nil)
+		}
+	}
+	for i := range a {
+		if target[i] != deleted {
+			current = append(current, i)
+		}
+	}
+	settled := make(map[int]bool, len(a))
+	for i := range a {
+		if target[i] != deleted && !moved[target[i]] {
+			settled[i] = true
+		}
+	}
+	for j := range b {
+		if !moved[j] {
+			continue
+		}
+		from := indexOf(current, source[j])
+		current = append(current[:from], current[from+1:]...)
+		to := 0
+		for k := j - 1; k >= 0; k-- {
+			if source[k] != added && settled[source[k]] {
+				to = indexOf(current, source[k]) + 1
+				break
+			}
+		}
+		current = append(current[:to], append([]int{source[j]}, current[to:]...)...)
+		settled[source[j]] = true
+		if from != to {
+			d.operation("move", pointer+"/"+strconv.Itoa(to), pointer+"/"+strconv.Itoa(from), nil)
+		}
+	}
+	for j := range b {
+		if source[j] == added {
+			d.operation("add", pointer+"/"+strconv.Itoa(j), "", b[j])
+		}
+	}
+	for j := range b {
+		if source[j] != added && !kept[j] && !moved[j] {
+			d.diff(pointer+"/"+strconv.Itoa(j), a[source[j]], b[j])
+		}
+	}
+}
+
+// operation appends the operation to the patch
+func (d *differ) operation(op, path, from string, value *Node) {
+	members := map[string]*Node{
+		"op":   StringNode("", op),
+		"path": StringNode("", path),
+	}
+	if op == "move" {
+		members["from"] = StringNode("", from)
+	}
+	if value != nil {
+		members["value"] = value.Clone()
+	}
+	d.patch = append(d.patch, ObjectNode("", members))
+}
+
+// commonSubsequence returns the pairs of indexes of the longest common subsequence of equal elements of a and b
+func commonSubsequence(a, b []*Node) (result [][2]int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && equal(a[prefix], b[prefix]) {
+		result = append(result, [2]int{prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && equal(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+	n, m := len(a)-prefix-suffix, len(b)-prefix-suffix
+	lengths := make([][]int, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if equal(a[prefix+i], b[prefix+j]) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case equal(a[prefix+i], b[prefix+j]):
+			result = append(result, [2]int{prefix + i, prefix + j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	for k := suffix; k > 0; k-- {
+		result = append(result, [2]int{len(a) - k, len(b) - k})
+	}
+	return result
+}
+
+// equal returns true if nodes are equal, see Node.Eq
+func equal(a, b *Node) bool {
+	result, err := a.Eq(b)
+	return err == nil && result
+}
+
+// indexOf returns the index of the value in the slice, or -1
+func indexOf(slice []int, value int) int {
+	for i, current := range slice {
+		if current == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,97 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		moves    bool
+		expected string
+	}{
+		{name: "equal", a: `{"a":[1,{"b":2}]}`, b: `{"a":[1,{"b":2.0}]}`, expected: `[]`},
+		{name: "root", a: `{"a":1}`, b: `[1]`, expected: `[{"op":"replace","path":"","value":[1]}]`},
+		{name: "scalar", a: `{"a":1,"b":"c"}`, b: `{"a":1,"b":"d"}`, expected: `[{"op":"replace","path":"/b","value":"d"}]`},
+		{name: "add and remove keys", a: `{"a":1,"b~/":2}`, b: `{"a":1,"c":{"d":3}}`, expected: `[{"op":"remove","path":"/b~0~1"},{"op":"add","path":"/c","value":{"d":3}}]`},
+		{name: "nested", a: `{"a":{"b":{"c":1,"d":2}}}`, b: `{"a":{"b":{"c":1,"d":3}}}`, expected: `[{"op":"replace","path":"/a/b/d","value":3}]`},
+		{name: "append", a: `[1,2]`, b: `[1,2,3,4]`, expected: `[{"op":"add","path":"/2","value":3},{"op":"add","path":"/3","value":4}]`},
+		{name: "insert", a: `[1,2,3]`, b: `[0,1,2,2.5,3]`, expected: `[{"op":"add","path":"/0","value":0},{"op":"add","path":"/3","value":2.5}]`},
+		{name: "remove", a: `[1,2,3,4]`, b: `[2,4]`, expected: `[{"op":"remove","path":"/2"},{"op":"remove","path":"/0"}]`},
+		{name: "change element", a: `[1,{"a":1},3]`, b: `[1,{"a":2},3]`, expected: `[{"op":"replace","path":"/1/a","value":2}]`},
+		{name: "change and add", a: `[1,2,3]`, b: `[1,4,5,3]`, expected: `[{"op":"add","path":"/2","value":5},{"op":"replace","path":"/1","value":4}]`},
+		{name: "without moves", a: `[1,2,3]`, b: `[2,3,1]`, expected: `[{"op":"remove","path":"/0"},{"op":"add","path":"/2","value":1}]`},
+		{name: "move to the end", a: `[1,2,3]`, b: `[2,3,1]`, moves: true, expected: `[{"from":"/0","op":"move","path":"/2"}]`},
+		{name: "move to the start", a: `[1,2,3]`, b: `[3,1,2]`, moves: true, expected: `[{"from":"/2","op":"move","path":"/0"}]`},
+		{name: "move and add", a: `[{"a":1},2,3]`, b: `[2,0,3,{"a":1}]`, moves: true, expected: `[{"from":"/0","op":"move","path":"/2"},{"op":"add","path":"/1","value":0}]`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := Must(Unmarshal([]byte(test.a)))
+			b := Must(Unmarshal([]byte(test.b)))
+			patch := DiffWithOptions(a, b, DiffOptions{Moves: test.moves})
+			if result := patch.String(); result != test.expected {
+				t.Errorf("Diff() wrong result:\n%s\nexpected:\n%s", result, test.expected)
+			}
+			if err := ApplyPatch(a, patch); err != nil {
+				t.Fatalf("ApplyPatch() unexpected error: %s", err)
+			}
+			if !equal(a, b) {
+				t.Errorf("ApplyPatch() wrong result: %s, expected: %s", a, b)
+			}
+		})
+	}
+}
+
+func TestDiff_apply(t *testing.T) {
+	documents := []string{
+		`null`,
+		`[]`,
+		`{}`,
+		`[1,2,3,4,5,6]`,
+		`[6,5,4,3,2,1]`,
+		`[1,[2,3],{"a":4},5,1,1]`,
+		`[{"a":4},[3,2],1,"x",1,5]`,
+		`[[1,2],[1,2],[3]]`,
+		`[[3],[1,2,4],"y"]`,
+		`{"a":{"b":[1,2,{"c":3}]},"d":[true,false,null],"e":"f"}`,
+		`{"a":{"b":[{"c":3},2,1]},"d":[null,true],"g":{"e":"f"}}`,
+		`{"a":[],"d":{"0":1}}`,
+	}
+	for _, moves := range []bool{false, true} {
+		for _, source := range documents {
+			for _, target := range documents {
+				a := Must(Unmarshal([]byte(source)))
+				b := Must(Unmarshal([]byte(target)))
+				patch := DiffWithOptions(a, b, DiffOptions{Moves: moves})
+				if err := ApplyPatch(a, patch); err != nil {
+					t.Errorf("ApplyPatch(%s, %s) unexpected error: %s", source, patch, err)
+					continue
+				}
+				if !equal(a, b) {
+					t.Errorf("ApplyPatch(%s, %s) wrong result: %s, expected: %s", source, patch, a, target)
+				}
+				if source == target && patch.Size() != 0 {
+					t.Errorf("Diff(%s, %s) must be empty: %s", source, target, patch)
+				}
+			}
+		}
+	}
+
+	if patch := Diff(nil, nil); patch.String() != `[]` {
+		t.Errorf("Diff(nil, nil) wrong result: %s", patch)
+	}
+}
+
+func ExampleDiff() {
+	a := Must(Unmarshal([]byte(`{"name": "ajson", "tags": ["json", "go"], "stars": 1}`)))
+	b := Must(Unmarshal([]byte(`{"name": "ajson", "tags": ["go", "json"], "stars": 2}`)))
+	fmt.Println(Diff(a, b))
+	fmt.Println(DiffWithOptions(a, b, DiffOptions{Moves: true}))
+	// Output:
+	// [{"op":"remove","path":"/tags/0"},{"op":"add","path":"/tags/1","value":"json"},{"op":"replace","path":"/stars","value":2}]
+	// [{"from":"/tags/0","op":"move","path":"/tags/1"},{"op":"replace","path":"/stars","value":2}]
+}
@@ -1,11 +1,18 @@
 package ajson
 
 import (
+	"math"
 	"strconv"
 )
 
-// Marshal returns slice of bytes, marshaled from current value
+// Marshal returns slice of bytes, marshaled from current value. Keys of objects are written in the order of Node.Keys.
+// Numeric values Infinity and NaN can't be marshaled, WrongRequest error is returned for them.
 func Marshal(node *Node) (result []byte, err error) {
+	return MarshalWithOptions(node, Options{})
+}
+
+// MarshalWithOptions returns slice of bytes, marshaled from current value with the given options, see Options.SortKeys.
+func MarshalWithOptions(node *Node, options Options) (result []byte, err error) {
 	result = make([]byte, 0)
 	var (
 		sValue string
@@ -16,7 +23,7 @@ func Marshal(node *Node) (result []byte, err error) {
 
 	if node == nil {
 		return nil, errorUnparsed()
-	} else if node.dirty {
+	} else if node.dirty || (options.SortKeys && node.isContainer()) {
 		switch node._type {
 		case Null:
 			result = append(result, _null...)
@@ -25,6 +32,9 @@ func Marshal(node *Node) (result []byte, err error) {
 			if err != nil {
 				return nil, err
 			}
+			if math.IsInf(nValue, 0) || math.IsNaN(nValue) {
+				return nil, errorRequest("unsupported numeric value: %v", nValue)
+			}
 			result = append(result, strconv.FormatFloat(nValue, 'g', -1, 64)...)
 		case String:
 			sValue, err = node.GetString()
@@ -53,7 +63,7 @@ func Marshal(node *Node) (result []byte, err error) {
 				if !ok {
 					return nil, errorRequest("wrong length of array")
 				}
-				oValue, err = Marshal(child)
+				oValue, err = MarshalWithOptions(child, options)
 				if err != nil {
 					return nil, err
 				}
@@ -63,7 +73,12 @@ func Marshal(node *Node) (result []byte, err error) {
 		case Object:
 			result = append(result, bracesL)
 			bValue = false
-			for key, child := range node.children {
+			keys := node.order
+			if options.SortKeys {
+				keys = sortedKeys(node.children)
+			}
+			for _, key := range keys {
+				child := node.children[key]
 				if bValue {
 					result = append(result, coma)
 				} else {
@@ -72,7 +87,7 @@ func Marshal(node *Node) (result []byte, err error) {
 				result = append(result, quotes)
 				result = append(result, quoteString(key, true)...)
 				result = append(result, quotes, colon)
-				oValue, err = Marshal(child)
+				oValue, err = MarshalWithOptions(child, options)
 				if err != nil {
 					return nil, err
 				}
@@ -88,3 +103,26 @@ func Marshal(node *Node) (result []byte, err error) {
 
 	return
 }
+
+// compact removes insignificant whitespaces from the marshaled value
+func compact(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	str, escaped := false, false
+	for _, c := range data {
+		if str {
+			if escaped {
+				escaped = false
+			} else if c == backslash {
+				escaped = true
+			} else if c == quotes {
+				str = false
+			}
+		} else if c == quotes {
+			str = true
+		} else if c == skipS || c == skipN || c == skipR || c == skipT {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
@@ -125,6 +125,44 @@ func TestMarshal_Encoded(t *testing.T) {
 	}
 }
 
+func TestMarshal_order(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"z": 1, "a": {"y": 2, "b": 3}, "m": [{"x": 4, "c": 5}]}`)))
+	if err := root.AppendObject("d", NullNode("")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.AppendObject("z", NumericNode("", 6)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.MustKey("a").DeleteKey("y"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	if err := root.MustKey("a").AppendObject("y", NumericNode("", 7)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		options  Options
+		expected string
+	}{
+		{name: "source order", expected: `{"z":6,"a":{"b":3,"y":7},"m":[{"x": 4, "c": 5}],"d":null}`},
+		{name: "sorted", options: Options{SortKeys: true}, expected: `{"a":{"b":3,"y":7},"d":null,"m":[{"c":5,"x":4}],"z":6}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				result, err := MarshalWithOptions(root, test.options)
+				if err != nil {
+					t.Fatalf("MarshalWithOptions() unexpected error: %s", err)
+				}
+				if string(result) != test.expected {
+					t.Fatalf("MarshalWithOptions() wrong result: %s", result)
+				}
+			}
+		})
+	}
+}
+
 func TestMarshal_Errors(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1,6 +1,10 @@
 package ajson
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
 
 // Error is common struct to provide internal errors
 type Error struct {
@@ -9,6 +13,12 @@ type Error struct {
 	Char    byte
 	Message string
 	Value   interface{}
+	// Line and Column are the 1-based position of Index in the source, Column is counted in characters.
+	// They are set only for errors of parsing: WrongSymbol, UnexpectedEOF, LimitExceeded and DuplicateKey.
+	Line   int
+	Column int
+	// Excerpt is the line of the source around the error, with the caret marker under the error position on the next line.
+	Excerpt string
 }
 
 // ErrorType is container for reflection type of error
@@ -27,6 +37,16 @@ const (
 	Unparsed
 	// UnsupportedType means that wrong type was given
 	UnsupportedType
+	// LimitExceeded means that one of the parsing limits was exceeded, Message contains the name of the limit
+	LimitExceeded
+	// DuplicateKey means that object has the same key more than once, Message contains the key
+	DuplicateKey
+	// WrongTarget means that node can't be stored into the Go value, Message contains the path of the node and Value
+	// contains the type of the Go value
+	WrongTarget
+	// WrongPatch means that the operation of JSON Patch failed, Index contains the index of the operation and Value
+	// contains the cause
+	WrongPatch
 )
 
 func errorSymbol(b *buffer) error {
@@ -56,12 +76,54 @@ func errorEOF(b *buffer) error {
 	}
 }
 
+func errorLimit(index int, limit string, value int) error {
+	return Error{
+		Type:    LimitExceeded,
+		Index:   index,
+		Message: limit,
+		Value:   value,
+	}
+}
+
+func errorDuplicate(index int, key string) error {
+	return Error{
+		Type:    DuplicateKey,
+		Index:   index,
+		Message: key,
+	}
+}
+
 func errorType() error {
 	return Error{
 		Type: WrongType,
 	}
 }
 
+func errorOverflow(value string, target string) error {
+	return Error{
+		Type:    WrongType,
+		Message: fmt.Sprintf("value %s can't be represented as %s", value, target),
+		Value:   value,
+	}
+}
+
+func errorTarget(node *Node, target interface{}) error {
+	return Error{
+		Type:    WrongTarget,
+		Message: node.Path(),
+		Value:   target,
+	}
+}
+
+func errorPatch(index int, cause error) error {
+	return Error{
+		Type:    WrongPatch,
+		Index:   index,
+		Message: cause.Error(),
+		Value:   cause,
+	}
+}
+
 func unsupportedType(value interface{}) error {
 	return Error{
 		Type:  UnsupportedType,
@@ -90,6 +152,9 @@ func (err Error) Error() string {
 	case UnexpectedEOF:
 		return "unexpected end of file"
 	case WrongType:
+		if err.Message != "" {
+			return fmt.Sprintf("wrong type of Node: %s", err.Message)
+		}
 		return "wrong type of Node"
 	case UnsupportedType:
 		return fmt.Sprintf("unsupported type was given: '%T'", err.Value)
@@ -97,6 +162,74 @@ func (err Error) Error() string {
 		return "not parsed yet"
 	case WrongRequest:
 		return fmt.Sprintf("wrong request: %s", err.Message)
+	case LimitExceeded:
+		return fmt.Sprintf("limit %s (%v) exceeded at %d", err.Message, err.Value, err.Index)
+	case DuplicateKey:
+		return fmt.Sprintf("duplicate key '%s' at %d", err.Message, err.Index)
+	case WrongTarget:
+		return fmt.Sprintf("node %s can't be stored into '%v'", err.Message, err.Value)
+	case WrongPatch:
+		return fmt.Sprintf("patch operation %d failed: %s", err.Index, err.Message)
 	}
 	return fmt.Sprintf("unknown error: '%s' at %d", []byte{err.Char}, err.Index)
 }
+
+// excerptSize is the maximum count of bytes around the error position in Error.Excerpt
+const excerptSize = 40
+
+// locate sets the line, column and excerpt of the parsing error, based on the source data
+func locate(err error, data []byte) error {
+	current, ok := err.(Error)
+	if !ok || current.Line != 0 || current.Index < 0 || current.Index > len(data) {
+		return err
+	}
+	switch current.Type {
+	case WrongSymbol, UnexpectedEOF, LimitExceeded, DuplicateKey:
+	default:
+		return err
+	}
+	var start int
+	current.Line, current.Column, start = position(data, current.Index)
+
+	end := bytes.IndexByte(data[current.Index:], skipN)
+	if end == -1 {
+		end = len(data)
+	} else {
+		end += current.Index
+	}
+	prefix, suffix := "", ""
+	if current.Index-start > excerptSize {
+		start = current.Index - excerptSize
+		for start < current.Index && !utf8.RuneStart(data[start]) {
+			start++
+		}
+		prefix = "..."
+	}
+	if end-current.Index > excerptSize {
+		end = current.Index + excerptSize
+		for end > current.Index && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		suffix = "..."
+	}
+	line := bytes.TrimRight(data[start:end], "\r")
+	marker := make([]byte, 0, len(prefix)+current.Index-start+1)
+	marker = append(marker, bytes.Repeat([]byte{skipS}, len(prefix))...)
+	for _, c := range string(data[start:current.Index]) {
+		if c == '\t' {
+			marker = append(marker, skipT)
+		} else {
+			marker = append(marker, skipS)
+		}
+	}
+	current.Excerpt = prefix + string(line) + suffix + "\n" + string(append(marker, caret))
+	return current
+}
+
+// position returns the 1-based line and column (in characters) of the index in data, and the index of the line start
+func position(data []byte, index int) (line, column, start int) {
+	line = 1 + bytes.Count(data[:index], []byte{skipN})
+	start = bytes.LastIndexByte(data[:index], skipN) + 1
+	column = 1 + utf8.RuneCount(data[start:index])
+	return
+}
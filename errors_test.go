@@ -1,6 +1,9 @@
 package ajson
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestError_Error(t *testing.T) {
 	tests := []struct {
@@ -10,8 +13,11 @@ func TestError_Error(t *testing.T) {
 	}{
 		{name: "WrongSymbol", _type: WrongSymbol, message: "wrong symbol 'S' at 10"},
 		{name: "UnexpectedEOF", _type: UnexpectedEOF, message: "unexpected end of file"},
-		{name: "WrongType", _type: WrongType, message: "wrong type of Node"},
+		{name: "WrongType", _type: WrongType, message: "wrong type of Node: example error"},
 		{name: "WrongRequest", _type: WrongRequest, message: "wrong request: example error"},
+		{name: "DuplicateKey", _type: DuplicateKey, message: "duplicate key 'example error' at 10"},
+		{name: "LimitExceeded", _type: LimitExceeded, message: "limit example error (<nil>) exceeded at 10"},
+		{name: "WrongTarget", _type: WrongTarget, message: "node example error can't be stored into '<nil>'"},
 		{name: "unknown", _type: -666, message: "unknown error: 'S' at 10"},
 	}
 	for _, test := range tests {
@@ -83,3 +89,125 @@ func Test_unsupportedType(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshal_errorPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		line    int
+		column  int
+		excerpt string
+	}{
+		{name: "first line", input: `[1, 2,]`, line: 1, column: 7, excerpt: "[1, 2,]\n      ^"},
+		{name: "next line", input: "{\n  \"a\": [1, 2,, 3]\n}", line: 2, column: 14, excerpt: "  \"a\": [1, 2,, 3]\n             ^"},
+		{name: "tabs", input: "{\n\t\"a\": x\n}", line: 2, column: 7, excerpt: "\t\"a\": x\n\t     ^"},
+		{name: "unicode", input: `["ключ", x]`, line: 1, column: 10, excerpt: "[\"ключ\", x]\n         ^"},
+		{name: "CRLF", input: "[\r\n1 2]", line: 2, column: 3, excerpt: "1 2]\n  ^"},
+		{name: "EOF", input: "[1,\n", line: 2, column: 1, excerpt: "\n^"},
+		{
+			name:    "long line",
+			input:   `["` + strings.Repeat("a", 50) + `", x, "` + strings.Repeat("b", 50) + `"]`,
+			line:    1,
+			column:  56,
+			excerpt: `...` + strings.Repeat("a", 37) + `", x, "` + strings.Repeat("b", 36) + "...\n" + strings.Repeat(" ", 43) + "^",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Unmarshal([]byte(test.input))
+			if err == nil {
+				t.Fatalf("Unmarshal() expected error")
+			}
+			current, ok := err.(Error)
+			if !ok {
+				t.Fatalf("Unmarshal() wrong error type: %T", err)
+			}
+			if current.Line != test.line || current.Column != test.column {
+				t.Errorf("wrong position: line %d, column %d, expected: line %d, column %d", current.Line, current.Column, test.line, test.column)
+			}
+			if current.Excerpt != test.excerpt {
+				t.Errorf("wrong excerpt:\n%s\nexpected:\n%s", current.Excerpt, test.excerpt)
+			}
+		})
+	}
+}
+
+func TestParseJSONPath_errorPosition(t *testing.T) {
+	_, err := ParseJSONPath("$.store[0")
+	if current, ok := err.(Error); !ok || current.Line != 1 || current.Column != 10 || current.Excerpt != "$.store[0\n         ^" {
+		t.Errorf("ParseJSONPath() wrong error: %#v", err)
+	}
+	_, err = Eval(NullNode(""), "1 + * 2")
+	if current, ok := err.(Error); !ok || current.Line != 1 || current.Column != 5 || current.Excerpt != "1 + * 2\n    ^" {
+		t.Errorf("Eval() wrong error: %#v", err)
+	}
+}
+
+func TestDecoder_Decode_errorPosition(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader("{\"a\": 1}\n[1,\n 2] [3 4]"))
+	for i := 0; i < 2; i++ {
+		if _, err := decoder.Decode(); err != nil {
+			t.Fatalf("Decode() unexpected error: %s", err)
+		}
+	}
+	_, err := decoder.Decode()
+	current, ok := err.(Error)
+	if !ok || current.Index != 20 || current.Line != 3 || current.Column != 8 {
+		t.Errorf("Decode() wrong error: %#v", err)
+	}
+}
+
+func TestLinesReader_Read_errorPosition(t *testing.T) {
+	reader := NewLinesReader(strings.NewReader("{\"a\": 1}\n{\"b\": x}\n"))
+	if _, _, err := reader.Read(); err != nil {
+		t.Fatalf("Read() unexpected error: %s", err)
+	}
+	_, _, err := reader.Read()
+	if current, ok := err.(Error); !ok || current.Line != 2 || current.Column != 7 {
+		t.Errorf("Read() wrong error: %#v", err)
+	}
+}
+
+func TestError_unterminatedString(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func() error
+		_type ErrorType
+	}{
+		{name: "Unmarshal", _type: WrongSymbol, parse: func() error {
+			_, err := Unmarshal([]byte(`["abc`))
+			return err
+		}},
+		{name: "Unmarshal key", _type: WrongSymbol, parse: func() error {
+			_, err := Unmarshal([]byte(`{"abc`))
+			return err
+		}},
+		{name: "ParseJSONPath", _type: UnexpectedEOF, parse: func() error {
+			_, err := ParseJSONPath(`$['abc`)
+			return err
+		}},
+		{name: "Eval", _type: UnexpectedEOF, parse: func() error {
+			_, err := Eval(NullNode(""), `'abc`)
+			return err
+		}},
+		{name: "Parser", _type: UnexpectedEOF, parse: func() error {
+			parser := NewParser()
+			if _, err := parser.Write([]byte(`{"abc`)); err != nil {
+				return err
+			}
+			return parser.Close()
+		}},
+		{name: "Decoder", _type: UnexpectedEOF, parse: func() error {
+			_, err := NewDecoder(strings.NewReader(`["abc`)).Decode()
+			return err
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.parse()
+			if current, ok := err.(Error); !ok || current.Type != test._type {
+				t.Errorf("wrong error: %#v", err)
+			}
+		})
+	}
+}
@@ -200,7 +200,16 @@ func recursiveChildren(node *Node) (result []*Node) {
 // 	result, _ := ParseJSONPath("$.store.book[?(@.price < 10)].title")
 // 	result == []string{"$", "store", "book", "?(@.price < 10)", "title"}
 //
+// Error of the parsing contains the line, column and excerpt of the path, see Error.
 func ParseJSONPath(path string) (result []string, err error) {
+	result, err = parseJSONPath(path)
+	if err != nil {
+		return nil, locate(err, []byte(path))
+	}
+	return result, nil
+}
+
+func parseJSONPath(path string) (result []string, err error) {
 	buf := newBuffer([]byte(path))
 	result = make([]string, 0)
 	const (
@@ -582,7 +591,7 @@ func ApplyJSONPath(node *Node, commands []string) (result []*Node, err error) {
 func Eval(node *Node, cmd string) (result *Node, err error) {
 	calc, err := newBuffer([]byte(cmd)).rpn()
 	if err != nil {
-		return nil, err
+		return nil, locate(err, []byte(cmd))
 	}
 	return eval(node, calc, cmd)
 }
@@ -623,7 +632,7 @@ func eval(node *Node, expression rpn, cmd string) (result *Node, err error) {
 			stack = stack[:size-1]
 		} else if len(exp) > 0 {
 			if exp[0] == dollar || exp[0] == at {
-				commands, err = ParseJSONPath(exp)
+				commands, err = parseJSONPath(exp)
 				if err != nil {
 					return
 				}
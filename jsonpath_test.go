@@ -140,14 +140,14 @@ func TestJsonPath(t *testing.T) {
 	}{
 		{name: "root", path: "$", expected: "[$]"},
 		{name: "roots", path: "$.", expected: "[$]"},
-		{name: "all objects", path: "$..", expected: "[$, $['store'], $['store']['bicycle'], $['store']['book'], $['store']['book'][0], $['store']['book'][1], $['store']['book'][2], $['store']['book'][3]]"},
+		{name: "all objects", path: "$..", expected: "[$, $['store'], $['store']['book'], $['store']['bicycle'], $['store']['book'][0], $['store']['book'][1], $['store']['book'][2], $['store']['book'][3]]"},
 		{name: "only children", path: "$.*", expected: "[$['store']]"},
 
 		{name: "by key", path: "$.store.bicycle", expected: "[$['store']['bicycle']]"},
 		{name: "all key 1", path: "$..bicycle", expected: "[$['store']['bicycle']]"},
 		{name: "all key 2", path: "$..price", expected: "[$['store']['bicycle']['price'], $['store']['book'][0]['price'], $['store']['book'][1]['price'], $['store']['book'][2]['price'], $['store']['book'][3]['price']]"},
 		{name: "all key bracket", path: "$..['price']", expected: "[$['store']['bicycle']['price'], $['store']['book'][0]['price'], $['store']['book'][1]['price'], $['store']['book'][2]['price'], $['store']['book'][3]['price']]"},
-		{name: "all fields", path: "$['store']['book'][1].*", expected: "[$['store']['book'][1]['author'], $['store']['book'][1]['category'], $['store']['book'][1]['price'], $['store']['book'][1]['title']]"},
+		{name: "all fields", path: "$['store']['book'][1].*", expected: "[$['store']['book'][1]['category'], $['store']['book'][1]['author'], $['store']['book'][1]['title'], $['store']['book'][1]['price']]"},
 
 		{name: "union fields", path: "$['store']['book'][2]['author','price','title']", expected: "[$['store']['book'][2]['author'], $['store']['book'][2]['price'], $['store']['book'][2]['title']]"},
 		{name: "union indexes", path: "$['store']['book'][1,2]", expected: "[$['store']['book'][1], $['store']['book'][2]]"},
@@ -1004,7 +1004,7 @@ func TestJSONPath_comparison_consensus(t *testing.T) {
 			name:      `bracket_notation_with_wildcard_after_recursive_descent`,
 			selector:  `$..[*]`,
 			document:  `{"key": "value", "another key": {"complex": "string", "primitives": [0, 1]}}`,
-			consensus: `[{"complex": "string", "primitives": [0, 1]}, "value", "string", [0, 1], 0, 1]`,
+			consensus: `["value", {"complex": "string", "primitives": [0, 1]}, "string", [0, 1], 0, 1]`,
 			// consensus: `["string", "value", 0, 1, [0, 1], {"complex": "string", "primitives": [0, 1]}]`,
 		},
 		{
@@ -1035,7 +1035,7 @@ func TestJSONPath_comparison_consensus(t *testing.T) {
 			name:      `bracket_notation_with_wildcard_on_object`,
 			selector:  `$[*]`,
 			document:  `{"some": "string", "int": 42, "object": {"key": "value"}, "array": [0, 1]}`,
-			consensus: `[[0, 1], 42, {"key": "value"}, "string"]`,
+			consensus: `["string", 42, {"key": "value"}, [0, 1]]`,
 			// consensus: `["string", 42, [0, 1], {"key": "value"}]`,
 		},
 		{
@@ -1164,7 +1164,7 @@ func TestJSONPath_comparison_consensus(t *testing.T) {
 			name:      `dot_notation_with_wildcard_after_recursive_descent`,
 			selector:  `$..*`,
 			document:  `{"key": "value", "another key": {"complex": "string", "primitives": [0, 1]}}`,
-			consensus: `[{"complex": "string", "primitives": [0, 1]}, "value", "string", [0, 1], 0, 1]`,
+			consensus: `["value", {"complex": "string", "primitives": [0, 1]}, "string", [0, 1], 0, 1]`,
 			// consensus: `["string", "value", 0, 1, [0, 1], {"complex": "string", "primitives": [0, 1]}]`,
 		},
 		{
@@ -1202,7 +1202,7 @@ func TestJSONPath_comparison_consensus(t *testing.T) {
 			name:      `dot_notation_with_wildcard_on_object`,
 			selector:  `$.*`,
 			document:  `{"some": "string", "int": 42, "object": {"key": "value"}, "array": [0, 1]}`,
-			consensus: `[[0, 1], 42, {"key": "value"}, "string"]`,
+			consensus: `["string", 42, {"key": "value"}, [0, 1]]`,
 			// consensus: `["string", 42, [0, 1], {"key": "value"}]`,
 		},
 		{
@@ -0,0 +1,84 @@
+package ajson
+
+import (
+	"bufio"
+	"io"
+)
+
+// LinesReader reads records from the newline-delimited JSON input (JSON Lines, NDJSON), one JSON value per line.
+//
+// Empty lines are ignored.
+type LinesReader struct {
+	// SkipInvalid defines the behaviour for the malformed lines: if true, they will be skipped, instead of returning
+	// the error.
+	SkipInvalid bool
+
+	reader *bufio.Reader
+	line   int
+	err    error
+}
+
+// LinesWriter writes nodes into the output as newline-delimited JSON (JSON Lines, NDJSON), one JSON value per line.
+type LinesWriter struct {
+	writer io.Writer
+}
+
+// NewLinesReader returns a new reader for the newline-delimited JSON input.
+func NewLinesReader(r io.Reader) *LinesReader {
+	return &LinesReader{
+		reader: bufio.NewReader(r),
+	}
+}
+
+// Read reads the next record from the input and returns its root node with the number of line (1-based) it was found on.
+// When there are no more records, Read returns io.EOF error.
+//
+// Error of the malformed line will be returned together with the number of that line, and reading can be continued
+// with the next line. Index and Column of such error are relative to the line.
+func (r *LinesReader) Read() (root *Node, line int, err error) {
+	var data []byte
+	for r.err == nil {
+		data, r.err = r.reader.ReadBytes(skipN)
+		if r.err != nil && (r.err != io.EOF || len(data) == 0) {
+			break
+		}
+		r.line++
+		if len(data) == 0 {
+			continue
+		}
+		root, err = Unmarshal(data)
+		if err != nil {
+			if _, empty := newBuffer(data).first(); empty != nil {
+				continue
+			}
+			if r.SkipInvalid {
+				continue
+			}
+			if current, ok := err.(Error); ok && current.Line > 0 {
+				current.Line = r.line
+				err = current
+			}
+			return nil, r.line, err
+		}
+		return root, r.line, nil
+	}
+	return nil, r.line, r.err
+}
+
+// NewLinesWriter returns a new writer of the newline-delimited JSON output.
+func NewLinesWriter(w io.Writer) *LinesWriter {
+	return &LinesWriter{
+		writer: w,
+	}
+}
+
+// Write marshals the node into a single line and writes it into the output.
+func (w *LinesWriter) Write(node *Node) (err error) {
+	result, err := Marshal(node)
+	if err != nil {
+		return err
+	}
+	result = append(compact(result), skipN)
+	_, err = w.writer.Write(result)
+	return err
+}
@@ -0,0 +1,137 @@
+package ajson
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLinesReader_Read(t *testing.T) {
+	type record struct {
+		line  int
+		value string
+		err   bool
+	}
+	tests := []struct {
+		name   string
+		input  string
+		skip   bool
+		result []record
+	}{
+		{
+			name:   "empty",
+			input:  "",
+			result: []record{},
+		},
+		{
+			name:  "simple",
+			input: "{\"id\":1}\n{\"id\":2}\n",
+			result: []record{
+				{line: 1, value: `{"id":1}`},
+				{line: 2, value: `{"id":2}`},
+			},
+		},
+		{
+			name:  "no trailing newline",
+			input: "1\n\"2\"",
+			result: []record{
+				{line: 1, value: `1`},
+				{line: 2, value: `"2"`},
+			},
+		},
+		{
+			name:  "crlf and empty lines",
+			input: "[1]\r\n\r\n  \n[2]\r\n",
+			result: []record{
+				{line: 1, value: `[1]`},
+				{line: 4, value: `[2]`},
+			},
+		},
+		{
+			name:  "malformed",
+			input: "[1]\n[2\n[3]\n",
+			result: []record{
+				{line: 1, value: `[1]`},
+				{line: 2, err: true},
+				{line: 3, value: `[3]`},
+			},
+		},
+		{
+			name:  "skip malformed",
+			input: "[1]\n[2\n{]\n[3]\n",
+			skip:  true,
+			result: []record{
+				{line: 1, value: `[1]`},
+				{line: 4, value: `[3]`},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := NewLinesReader(strings.NewReader(test.input))
+			reader.SkipInvalid = test.skip
+			result := make([]record, 0)
+			for {
+				root, line, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					result = append(result, record{line: line, err: true})
+				} else {
+					result = append(result, record{line: line, value: string(root.Source())})
+				}
+			}
+			if fmt.Sprint(result) != fmt.Sprint(test.result) {
+				t.Errorf("Read() wrong result: %v, expected: %v", result, test.result)
+			}
+		})
+	}
+}
+
+func TestLinesWriter_Write(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	writer := NewLinesWriter(buf)
+	root := Must(Unmarshal([]byte("{\n  \"foo\": [ 1, 2 ],\n  \"bar\": \"new\\nline \\\" \"\n}")))
+	if err := writer.Write(root); err != nil {
+		t.Fatalf("Write() unexpected error: %s", err)
+	}
+	if err := writer.Write(StringNode("", "multi\nline")); err != nil {
+		t.Fatalf("Write() unexpected error: %s", err)
+	}
+	if err := writer.Write(&Node{}); err == nil {
+		t.Errorf("Write() expected error")
+	}
+	expected := "{\"foo\":[1,2],\"bar\":\"new\\nline \\\" \"}\n\"multi\\nline\"\n"
+	if buf.String() != expected {
+		t.Errorf("Write() wrong result: %s", buf.String())
+	}
+
+	reader := NewLinesReader(buf)
+	for i := 0; i < 2; i++ {
+		if _, _, err := reader.Read(); err != nil {
+			t.Errorf("Read() unexpected error: %s", err)
+		}
+	}
+}
+
+func ExampleLinesReader() {
+	reader := NewLinesReader(strings.NewReader("{\"level\":\"info\"}\n{\"level\":\n{\"level\":\"error\"}\n"))
+	for {
+		root, line, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Printf("line %d: %s\n", line, err)
+			continue
+		}
+		fmt.Printf("line %d: %s\n", line, root.MustKey("level").MustString())
+	}
+	// Output:
+	// line 1: info
+	// line 2: unexpected end of file
+	// line 3: error
+}
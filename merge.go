@@ -0,0 +1,141 @@
+package ajson
+
+// Merge deeply merges the right node into the left one, with the default strategies: arrays and values of different
+// types are replaced. It returns the paths of the left nodes, that were overridden, see MergeWithOptions.
+func Merge(left, right *Node) ([]string, error) {
+	return MergeWithOptions(left, right, MergeOptions{})
+}
+
+// MergeWithOptions deeply merges the right node into the left one, e.g. to apply layers of configuration one after
+// another. Right node is not changed.
+//
+// Objects are merged key by key: new keys are appended, values of the existing keys are merged recursively. Arrays
+// are merged with the MergeOptions.Arrays strategy, values of different types with the MergeOptions.Conflicts
+// strategy, other values are replaced with the right ones.
+//
+// Result is the list of paths (see Node.Path) of the left nodes, whose values were replaced. Merging is checked on the
+// clone of the left node first, and then made in place, so the left node stays unchanged on error, and its nodes,
+// that were not replaced, are kept with their observers.
+func MergeWithOptions(left, right *Node, options MergeOptions) ([]string, error) {
+	if left == nil || right == nil {
+		return nil, errorUnparsed()
+	}
+	if options.Arrays == MergeArraysByKey && options.Key == "" {
+		return nil, errorRequest("merge key is not set")
+	}
+	if err := (&merger{options: options}).merge(left.Clone(), right); err != nil {
+		return nil, err
+	}
+	m := &merger{options: options, overridden: make([]string, 0)}
+	err := left.atomically(func() error {
+		return m.merge(left, right)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.overridden, nil
+}
+
+// merger collects the overridden paths
+type merger struct {
+	options    MergeOptions
+	overridden []string
+}
+
+// merge merges the right node into the left one
+func (m *merger) merge(left, right *Node) error {
+	switch {
+	case left.IsObject() && right.IsObject():
+		for _, key := range right.Keys() {
+			current, ok := left.children[key]
+			if !ok {
+				if err := left.AppendObject(key, right.children[key].Clone()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := m.merge(current, right.children[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case left.IsArray() && right.IsArray():
+		return m.mergeArray(left, right)
+	case left.Type() != right.Type():
+		switch m.options.Conflicts {
+		case MergeConflictsLeft:
+			return nil
+		case MergeConflictsError:
+			return errorRequest("values of %s have different types", left.Path())
+		}
+	}
+	return m.replace(left, right)
+}
+
+// mergeArray merges the right array into the left one
+func (m *merger) mergeArray(left, right *Node) error {
+	switch m.options.Arrays {
+	case MergeArraysConcat:
+		for _, child := range right.Inheritors() {
+			if err := left.AppendArray(child.Clone()); err != nil {
+				return err
+			}
+		}
+	case MergeArraysUnion:
+		for _, child := range right.Inheritors() {
+			if !m.contains(left, child) {
+				if err := left.AppendArray(child.Clone()); err != nil {
+					return err
+				}
+			}
+		}
+	case MergeArraysByKey:
+		for _, child := range right.Inheritors() {
+			if current := m.find(left, child); current != nil {
+				if err := m.merge(current, child); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := left.AppendArray(child.Clone()); err != nil {
+				return err
+			}
+		}
+	default:
+		return m.replace(left, right)
+	}
+	return nil
+}
+
+// replace replaces the left value with the right one, if they are not equal
+func (m *merger) replace(left, right *Node) error {
+	if equal(left, right) {
+		return nil
+	}
+	m.overridden = append(m.overridden, left.Path())
+	return left.SetNode(right)
+}
+
+// contains returns true if the array contains the value
+func (m *merger) contains(array, value *Node) bool {
+	for _, child := range array.Inheritors() {
+		if equal(child, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the object of the array with the same value of the merge key as the given one, or nil
+func (m *merger) find(array, value *Node) *Node {
+	key, ok := value.children[m.options.Key]
+	if !value.IsObject() || !ok {
+		return nil
+	}
+	for _, child := range array.Inheritors() {
+		if current, ok := child.children[m.options.Key]; ok && child.IsObject() && equal(current, key) {
+			return child
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package ajson
+
+// This file contains the support of JSON Merge Patch (RFC 7386): https://tools.ietf.org/html/rfc7386
+
+// MergePatch applies JSON Merge Patch to the target node in place: members of the patch object replace members of
+// the target, null removes the member, objects are merged recursively; any other patch value replaces the target.
+//
+// Only changed nodes are marked as dirty: value, equal to the current one, doesn't change the target.
+func MergePatch(target, patch *Node) error {
+	if target == nil || patch == nil {
+		return errorUnparsed()
+	}
+	if !patch.IsObject() {
+		if equal(target, patch) {
+			return nil
+		}
+		return target.SetNode(patch)
+	}
+	if !target.IsObject() {
+		if err := target.SetObject(map[string]*Node{}); err != nil {
+			return err
+		}
+	}
+	for _, key := range patch.Keys() {
+		value := patch.children[key]
+		current, ok := target.children[key]
+		if value.IsNull() {
+			if ok {
+				if err := target.remove(current); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !ok {
+			current = NullNode(key)
+			if err := MergePatch(current, value); err != nil {
+				return err
+			}
+			if err := target.AppendObject(key, current); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := MergePatch(current, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateMergePatch returns JSON Merge Patch, that transforms the original node into the modified one: after
+// MergePatch(original, patch) the original node is equal to the modified.
+//
+// Merge patch can't set null as the value of the object member, so it returns error if the modified node has such a
+// new or changed value.
+func CreateMergePatch(original, modified *Node) (*Node, error) {
+	if original == nil || modified == nil {
+		return nil, errorUnparsed()
+	}
+	if !original.IsObject() || !modified.IsObject() {
+		if err := checkMergeValue(modified); err != nil {
+			return nil, err
+		}
+		return modified.Clone(), nil
+	}
+	patch := ObjectNode("", nil)
+	for _, key := range original.Keys() {
+		if !modified.HasKey(key) {
+			if err := patch.AppendObject(key, NullNode(key)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, key := range modified.Keys() {
+		value := modified.children[key]
+		current, ok := original.children[key]
+		if ok && equal(current, value) {
+			continue
+		}
+		if value.IsNull() {
+			return nil, errorRequest("null value of %s can't be set by merge patch", value.Path())
+		}
+		if !ok {
+			current = NullNode(key)
+		}
+		member, err := CreateMergePatch(current, value)
+		if err != nil {
+			return nil, err
+		}
+		if err = patch.AppendObject(key, member); err != nil {
+			return nil, err
+		}
+	}
+	return patch, nil
+}
+
+// checkMergeValue returns error if the object value, that is set by merge patch, contains null members
+func checkMergeValue(value *Node) error {
+	if !value.IsObject() {
+		return nil
+	}
+	for _, child := range value.Inheritors() {
+		if child.IsNull() {
+			return errorRequest("null value of %s can't be set by merge patch", child.Path())
+		}
+		if err := checkMergeValue(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	// examples from RFC 7386, Appendix A
+	tests := []struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		{target: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{target: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+		{target: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+		{target: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+		{target: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{target: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+		{target: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+		{target: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+		{target: `["a","b"]`, patch: `["c","d"]`, expected: `["c","d"]`},
+		{target: `{"a":"b"}`, patch: `["c"]`, expected: `["c"]`},
+		{target: `{"a":"foo"}`, patch: `null`, expected: `null`},
+		{target: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+		{target: `{"e":null}`, patch: `{"a":1}`, expected: `{"e":null,"a":1}`},
+		{target: `[1,2]`, patch: `{"a":"b","c":null}`, expected: `{"a":"b"}`},
+		{target: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+	}
+	for _, test := range tests {
+		t.Run(test.patch, func(t *testing.T) {
+			target := Must(Unmarshal([]byte(test.target)))
+			patch := Must(Unmarshal([]byte(test.patch)))
+			if err := MergePatch(target, patch); err != nil {
+				t.Fatalf("MergePatch() unexpected error: %s", err)
+			}
+			result, err := Marshal(target)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("MergePatch() wrong result: %s, expected: %s", result, test.expected)
+			}
+			if patch.String() != test.patch {
+				t.Errorf("MergePatch() changed the patch: %s", patch)
+			}
+		})
+	}
+
+	if err := MergePatch(nil, NullNode("")); err == nil {
+		t.Errorf("MergePatch() expected error for nil target")
+	}
+}
+
+func TestMergePatch_dirty(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": 1, "c": [1, 2]}, "d": {"e": "f"}}`)))
+	err := MergePatch(root, Must(Unmarshal([]byte(`{"a": {"b": 1, "c": [1, 2]}, "d": {"e": "g"}}`))))
+	if err != nil {
+		t.Fatalf("MergePatch() unexpected error: %s", err)
+	}
+	if root.MustKey("a").IsDirty() || root.MustKey("a").MustKey("c").IsDirty() {
+		t.Errorf("MergePatch() marked unchanged node as dirty")
+	}
+	if !root.IsDirty() || !root.MustKey("d").IsDirty() {
+		t.Errorf("MergePatch() didn't mark changed node as dirty")
+	}
+	if result := root.String(); result != `{"a":{"b": 1, "c": [1, 2]},"d":{"e":"g"}}` {
+		t.Errorf("MergePatch() wrong result: %s", result)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	tests := []struct {
+		original string
+		modified string
+		expected string
+		err      bool
+	}{
+		{original: `{"a":1,"b":2}`, modified: `{"a":1,"b":2}`, expected: `{}`},
+		{original: `{"a":1,"b":2}`, modified: `{"a":3,"c":4}`, expected: `{"b":null,"a":3,"c":4}`},
+		{original: `{"a":{"b":{"c":1,"d":2}}}`, modified: `{"a":{"b":{"c":1}}}`, expected: `{"a":{"b":{"d":null}}}`},
+		{original: `{"a":[1,2]}`, modified: `{"a":[1,null]}`, expected: `{"a":[1,null]}`},
+		{original: `{"a":1}`, modified: `{"a":{"b":{"c":[]}}}`, expected: `{"a":{"b":{"c":[]}}}`},
+		{original: `{"a":1}`, modified: `[1]`, expected: `[1]`},
+		{original: `[1]`, modified: `null`, expected: `null`},
+		{original: `{"a":null}`, modified: `{"a":null,"b":1}`, expected: `{"b":1}`},
+		{original: `{"a":1}`, modified: `{"a":null}`, err: true},
+		{original: `{"a":1}`, modified: `{"a":{"b":null}}`, err: true},
+		{original: `null`, modified: `{"a":{"b":null}}`, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.modified, func(t *testing.T) {
+			original := Must(Unmarshal([]byte(test.original)))
+			modified := Must(Unmarshal([]byte(test.modified)))
+			patch, err := CreateMergePatch(original, modified)
+			if test.err {
+				if err == nil {
+					t.Errorf("CreateMergePatch() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateMergePatch() unexpected error: %s", err)
+			}
+			result, err := Marshal(patch)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("CreateMergePatch() wrong result: %s, expected: %s", result, test.expected)
+			}
+			if err = MergePatch(original, patch); err != nil {
+				t.Fatalf("MergePatch() unexpected error: %s", err)
+			}
+			if !equal(original, modified) {
+				t.Errorf("MergePatch() wrong result: %s, expected: %s", original, modified)
+			}
+		})
+	}
+}
+
+func ExampleMergePatch() {
+	root := Must(Unmarshal([]byte(`{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"]}`)))
+	patch := Must(Unmarshal([]byte(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`)))
+	if err := MergePatch(root, patch); err != nil {
+		panic(err)
+	}
+	fmt.Println(root)
+	// Output:
+	// {"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"phoneNumber":"+01-123-456-7890"}
+}
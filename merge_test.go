@@ -0,0 +1,124 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMergeWithOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		left       string
+		right      string
+		options    MergeOptions
+		expected   string
+		overridden []string
+	}{
+		{name: "objects", left: `{"a":1,"b":{"c":2,"d":3}}`, right: `{"b":{"d":4,"e":5},"f":6}`, expected: `{"a":1,"b":{"c":2,"d":4,"e":5},"f":6}`, overridden: []string{"$['b']['d']"}},
+		{name: "equal values", left: `{"a":1,"b":[1]}`, right: `{"a":1.0,"b":[1]}`, expected: `{"a":1,"b":[1]}`, overridden: []string{}},
+		{name: "replace arrays", left: `{"a":[1,2]}`, right: `{"a":[3]}`, expected: `{"a":[3]}`, overridden: []string{"$['a']"}},
+		{name: "concat arrays", left: `{"a":[1,2]}`, right: `{"a":[2,3]}`, options: MergeOptions{Arrays: MergeArraysConcat}, expected: `{"a":[1,2,2,3]}`, overridden: []string{}},
+		{name: "union arrays", left: `{"a":[1,2]}`, right: `{"a":[2,3,3]}`, options: MergeOptions{Arrays: MergeArraysUnion}, expected: `{"a":[1,2,3]}`, overridden: []string{}},
+		{
+			name:       "merge arrays by key",
+			left:       `[{"id":1,"a":1},{"id":2,"a":2},3]`,
+			right:      `[{"id":2,"a":4,"b":5},{"id":6},{"a":7},3]`,
+			options:    MergeOptions{Arrays: MergeArraysByKey, Key: "id"},
+			expected:   `[{"id":1,"a":1},{"id":2,"a":4,"b":5},3,{"id":6},{"a":7},3]`,
+			overridden: []string{"$[1]['a']"},
+		},
+		{name: "conflict right", left: `{"a":{"b":1},"c":null}`, right: `{"a":[1],"c":"d"}`, expected: `{"a":[1],"c":"d"}`, overridden: []string{"$['a']", "$['c']"}},
+		{name: "conflict left", left: `{"a":{"b":1},"c":1}`, right: `{"a":[1],"c":2}`, options: MergeOptions{Conflicts: MergeConflictsLeft}, expected: `{"a":{"b":1},"c":2}`, overridden: []string{"$['c']"}},
+		{name: "root", left: `1`, right: `2`, expected: `2`, overridden: []string{"$"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			left := Must(Unmarshal([]byte(test.left)))
+			right := Must(Unmarshal([]byte(test.right)))
+			overridden, err := MergeWithOptions(left, right, test.options)
+			if err != nil {
+				t.Fatalf("MergeWithOptions() unexpected error: %s", err)
+			}
+			result, err := Marshal(left)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("MergeWithOptions() wrong result: %s, expected: %s", result, test.expected)
+			}
+			if !sliceEqual(overridden, test.overridden) {
+				t.Errorf("MergeWithOptions() wrong overridden paths: %s, expected: %s", sliceString(overridden), sliceString(test.overridden))
+			}
+			if right.String() != test.right {
+				t.Errorf("MergeWithOptions() changed the right node: %s", right)
+			}
+		})
+	}
+}
+
+func TestMergeWithOptions_errors(t *testing.T) {
+	left := Must(Unmarshal([]byte(`{"a": {"b": 1}, "c": 1}`)))
+	right := Must(Unmarshal([]byte(`{"c": 2, "a": [1]}`)))
+	if _, err := MergeWithOptions(left, right, MergeOptions{Conflicts: MergeConflictsError}); err == nil {
+		t.Errorf("MergeWithOptions() expected error")
+	}
+	if left.String() != `{"a": {"b": 1}, "c": 1}` || left.IsDirty() {
+		t.Errorf("MergeWithOptions() changed the left node: %s", left)
+	}
+	if _, err := MergeWithOptions(left, right, MergeOptions{Arrays: MergeArraysByKey}); err == nil {
+		t.Errorf("MergeWithOptions() expected error without merge key")
+	}
+	if _, err := Merge(nil, right); err == nil {
+		t.Errorf("Merge() expected error for nil node")
+	}
+}
+
+func TestMerge_inPlace(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"x": 1}, "b": 2}`)))
+	a := root.MustKey("a")
+	events := 0
+	a.Observe(func(event Event) {
+		events++
+	})
+	overridden, err := Merge(root, Must(Unmarshal([]byte(`{"b": 3}`))))
+	if err != nil {
+		t.Fatalf("Merge() unexpected error: %s", err)
+	}
+	if root.MustKey("a") != a || a.IsDirty() || !sliceEqual(overridden, []string{"$['b']"}) {
+		t.Errorf("Merge() changed the untouched node, overridden: %s", sliceString(overridden))
+	}
+	if err = a.MustKey("x").SetNumeric(5); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"a":{"x":5},"b":3}` || events != 1 {
+		t.Errorf("Merge() wrong result: %s, events: %d", result, events)
+	}
+
+	overridden, err = Merge(a, Must(Unmarshal([]byte(`{"x": 6}`))))
+	if err != nil {
+		t.Fatalf("Merge() unexpected error: %s", err)
+	}
+	if !sliceEqual(overridden, []string{"$['a']['x']"}) {
+		t.Errorf("Merge() wrong overridden paths: %s", sliceString(overridden))
+	}
+}
+
+func ExampleMergeWithOptions() {
+	config := Must(Unmarshal([]byte(`{"port": 80, "hosts": ["localhost"], "debug": false}`)))
+	layers := []string{
+		`{"port": 8080, "hosts": ["example.com"]}`,
+		`{"debug": true, "hosts": ["localhost"]}`,
+	}
+	for _, layer := range layers {
+		overridden, err := MergeWithOptions(config, Must(Unmarshal([]byte(layer))), MergeOptions{Arrays: MergeArraysUnion})
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(overridden)
+	}
+	fmt.Println(config)
+	// Output:
+	// [$['port']]
+	// [$['debug']]
+	// {"port":8080,"hosts":["localhost","example.com"],"debug":true}
+}
@@ -22,15 +22,38 @@ import (
 // Every type has its own methods to be called.
 // Every Node contains link to a byte data, parent and children, also calculated type of value, atomic value and internal information.
 type Node struct {
-	parent   *Node
-	children map[string]*Node
-	key      *string
-	index    *int
-	_type    NodeType
-	data     *[]byte
-	borders  [2]int
-	value    atomic.Value
-	dirty    bool
+	parent     *Node
+	children   map[string]*Node
+	duplicates map[string][]*Node
+	order      []string
+	key        *string
+	index      *int
+	_type      NodeType
+	data       *[]byte
+	borders    [2]int
+	value      atomic.Value
+	dirty      bool
+	// origin is the position of the data in the stream of the Decoder, see Node.Position
+	origin *origin
+	// constructed is true if the source of the node was given to its constructor, see NumericLiteralNode
+	constructed bool
+	// transaction is set only for the root node, see Node.Begin
+	transaction *Transaction
+	// observers are the callbacks of the changes of the node and its descendants, see Node.Observe
+	observers []*observer
+}
+
+// Position is the location of the node in the source data
+type Position struct {
+	// Offset and End are the byte offsets of the first symbol of the node and of the symbol right after the last one
+	Offset int
+	End    int
+	// Line and Column are the 1-based position of the first symbol, Column is counted in characters
+	Line   int
+	Column int
+	// EndLine and EndColumn are the 1-based position of the symbol right after the last one
+	EndLine   int
+	EndColumn int
 }
 
 // NodeType is a kind of reflection of JSON type to a type of golang
@@ -137,6 +160,7 @@ func ObjectNode(key string, value map[string]*Node) (current *Node) {
 			val.parent = current
 			val.key = &key
 		}
+		current.order = sortedKeys(value)
 	} else {
 		current.children = make(map[string]*Node)
 	}
@@ -147,6 +171,7 @@ func newNode(parent *Node, buf *buffer, _type NodeType, key **string) (current *
 	current = &Node{
 		parent:  parent,
 		data:    &buf.data,
+		origin:  buf.origin,
 		borders: [2]int{buf.index, 0},
 		_type:   _type,
 		key:     *key,
@@ -164,7 +189,7 @@ func newNode(parent *Node, buf *buffer, _type NodeType, key **string) (current *
 			if *key == nil {
 				err = errorSymbol(buf)
 			} else {
-				parent.children[**key] = current
+				parent.setChild(**key, current)
 				*key = nil
 			}
 		} else {
@@ -208,6 +233,22 @@ func (n *Node) Source() []byte {
 	return nil
 }
 
+// Position returns the location of the node in the source data, i.e. the data given to Unmarshal. Nodes of the Decoder
+// are located from the beginning of the stream, the same way as its parsing errors.
+//
+// Returns Unparsed error for nodes, that were not parsed from the data: created by constructors or replaced by setters.
+func (n *Node) Position() (result Position, err error) {
+	if n == nil || !n.ready() || n.data == nil || n.constructed {
+		return result, errorUnparsed()
+	}
+	data := *n.data
+	result.Line, result.Column, _ = position(data, n.borders[0])
+	result.EndLine, result.EndColumn, _ = position(data, n.borders[1])
+	result.Offset, result.Line, result.Column = n.origin.position(n.borders[0], result.Line, result.Column)
+	result.End, result.EndLine, result.EndColumn = n.origin.position(n.borders[1], result.EndLine, result.EndColumn)
+	return result, nil
+}
+
 // String is implementation of Stringer interface, returns string based on source part
 func (n *Node) String() string {
 	if n == nil {
@@ -261,14 +302,20 @@ func (n *Node) Size() int {
 	return len(n.children)
 }
 
-// Keys will return count all keys of children of current node, please check, that parent of this node has an Object type
+// Keys will return all keys of children of current node, please check, that parent of this node has an Object type.
+// Keys of the Object are returned in the order of the source, new keys are added to the end.
 func (n *Node) Keys() (result []string) {
 	if n == nil {
 		return nil
 	}
+	if n.IsObject() {
+		result = make([]string, len(n.order))
+		copy(result, n.order)
+		return
+	}
 	result = make([]string, 0, len(n.children))
-	for key := range n.children {
-		result = append(result, key)
+	for i := 0; i < len(n.children); i++ {
+		result = append(result, strconv.Itoa(i))
 	}
 	return
 }
@@ -661,6 +708,21 @@ func (n *Node) MustKey(key string) (value *Node) {
 	return
 }
 
+// Duplicates will return all values of the key in order they were found in the source, if the object was parsed with
+// the DuplicateKeysAll policy. The last one of them is the current child node. If key is unavailable, will return nil.
+func (n *Node) Duplicates(key string) []*Node {
+	if n == nil {
+		return nil
+	}
+	value, ok := n.children[key]
+	if !ok {
+		return nil
+	}
+	result := make([]*Node, 0, len(n.duplicates[key])+1)
+	result = append(result, n.duplicates[key]...)
+	return append(result, value)
+}
+
 // HasKey will return boolean value, if current object node has custom key
 func (n *Node) HasKey(key string) bool {
 	if n == nil {
@@ -906,7 +968,7 @@ func (n *Node) getUInteger() (uint, error) {
 	return uint(result), nil
 }
 
-// Inheritors return sorted by keys/index slice of children
+// Inheritors return slice of children: ordered by index for Array and in the order of keys (see Node.Keys) for Object
 func (n *Node) Inheritors() (result []*Node) {
 	if n == nil {
 		return nil
@@ -914,11 +976,7 @@ func (n *Node) Inheritors() (result []*Node) {
 	size := len(n.children)
 	if n.IsObject() {
 		result = make([]*Node, size)
-		keys := n.Keys()
-		sort.Slice(keys, func(i, j int) bool {
-			return keys[i] < keys[j]
-		})
-		for i, key := range keys {
+		for i, key := range n.order {
 			result[i] = n.children[key]
 		}
 	} else if n.IsArray() {
@@ -930,6 +988,16 @@ func (n *Node) Inheritors() (result []*Node) {
 	return
 }
 
+// sortedKeys returns keys of the map in the alphabetical order
+func sortedKeys(value map[string]*Node) []string {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // JSONPath evaluate path for current node
 func (n *Node) JSONPath(path string) (result []*Node, err error) {
 	commands, err := ParseJSONPath(path)
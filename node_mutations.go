@@ -77,11 +77,24 @@ func (n *Node) SetNode(value *Node) error {
 	if n.isParentOrSelfNode(value) {
 		return errorRequest("attempt to create infinite loop")
 	}
+	after, err := n.emit(func() Event {
+		return Event{Type: EventSet, Node: n, Path: n.Path(), Old: n.Clone(), New: value}
+	})
+	if err != nil {
+		return err
+	}
+	defer after()
 
+	defer n.record()()
 	node := value.Clone()
 	node.setReference(n.parent, n.key, n.index)
+	node.transaction = n.transaction
+	node.observers = n.observers
 	n.setReference(nil, nil, nil)
 	*n = *node
+	for _, child := range n.children {
+		child.parent = n
+	}
 	if n.parent != nil {
 		n.parent.mark()
 	}
@@ -93,13 +106,16 @@ func (n *Node) AppendArray(value ...*Node) error {
 	if !n.IsArray() {
 		return errorType()
 	}
-	for _, val := range value {
-		if err := n.appendNode(nil, val); err != nil {
-			return err
+	return n.several(value, func() error {
+		defer n.record()()
+		for _, val := range value {
+			if err := n.appendNode(nil, val); err != nil {
+				return err
+			}
 		}
-	}
-	n.mark()
-	return nil
+		n.mark()
+		return nil
+	})
 }
 
 // AppendObject appends current Object node value with key:value
@@ -115,6 +131,128 @@ func (n *Node) AppendObject(key string, value *Node) error {
 	return nil
 }
 
+// InsertIndex inserts Node values into current Array node value before the index, following values are shifted.
+// Index equal to the size of the array appends the values to the end, like AppendArray.
+func (n *Node) InsertIndex(index int, value ...*Node) error {
+	if !n.IsArray() {
+		return errorType()
+	}
+	if index < 0 || index > len(n.children) {
+		return errorRequest("out of index %d", index)
+	}
+	return n.several(value, func() error {
+		defer n.record()()
+		for _, val := range value {
+			if val.parent == n && *val.index < index {
+				// value is moved inside the same array, so its removal shifts the index
+				index--
+			}
+			if err := n.insertNode(index, val); err != nil {
+				return err
+			}
+			index++
+		}
+		n.mark()
+		return nil
+	})
+}
+
+// several makes the change of current node by several values, added one by one. Values are checked before the change,
+// and if any interceptor can cancel it in the middle, the values, added before, are reverted.
+func (n *Node) several(value []*Node, fn func() error) error {
+	for _, val := range value {
+		if n.isParentOrSelfNode(val) {
+			return errorRequest("attempt to create infinite loop")
+		}
+	}
+	if len(value) > 1 && intercepted(append([]*Node{n}, value...)...) {
+		return n.atomically(fn)
+	}
+	return fn()
+}
+
+// MoveTo moves current node into the parent: by key (string) for the Object, or before the index (int) for the Array,
+// see AppendObject and InsertIndex.
+func (n *Node) MoveTo(parent *Node, keyOrIndex interface{}) error {
+	if parent == nil {
+		return errorUnparsed()
+	}
+	switch value := keyOrIndex.(type) {
+	case string:
+		return parent.AppendObject(value, n)
+	case int:
+		return parent.InsertIndex(value, n)
+	}
+	return unsupportedType(keyOrIndex)
+}
+
+// RenameKey changes the key of the element of Object, element keeps its position in the keys order
+func (n *Node) RenameKey(from, to string) error {
+	node, err := n.GetKey(from)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return nil
+	}
+	if _, ok := n.children[to]; ok {
+		return errorRequest("key '%s' already exists", to)
+	}
+	after, err := n.emit(n.reorder)
+	if err != nil {
+		return err
+	}
+	defer after()
+	defer n.record(n.duplicates[from]...)()
+	n.order[indexOfKey(n.order, from)] = to
+	delete(n.children, from)
+	n.children[to] = node
+	if duplicates, ok := n.duplicates[from]; ok {
+		delete(n.duplicates, from)
+		n.duplicates[to] = duplicates
+		for _, duplicate := range duplicates {
+			duplicate.key = &to
+		}
+	}
+	node.key = &to
+	n.value = atomic.Value{}
+	n.mark()
+	return nil
+}
+
+// Swap exchanges the positions of two elements of current node: by indexes (int) for the Array, or by keys (string)
+// for the Object, elements of the Object keep their keys.
+func (n *Node) Swap(a, b interface{}) error {
+	first, err := n.child(a)
+	if err != nil {
+		return err
+	}
+	second, err := n.child(b)
+	if err != nil {
+		return err
+	}
+	if first == second {
+		return nil
+	}
+	after, err := n.emit(n.reorder)
+	if err != nil {
+		return err
+	}
+	defer after()
+	defer n.record()()
+	if n.IsArray() {
+		i, j := *first.index, *second.index
+		first.index, second.index = &j, &i
+		n.children[strconv.Itoa(i)], n.children[strconv.Itoa(j)] = second, first
+	} else {
+		i, j := indexOfKey(n.order, *first.key), indexOfKey(n.order, *second.key)
+		n.order[i], n.order[j] = n.order[j], n.order[i]
+	}
+	n.value = atomic.Value{}
+	n.mark()
+	return nil
+}
+
 // DeleteNode removes element child
 func (n *Node) DeleteNode(value *Node) error {
 	return n.remove(value)
@@ -173,18 +311,30 @@ func (n *Node) Clone() *Node {
 
 func (n *Node) clone() *Node {
 	node := &Node{
-		parent:   n.parent,
-		children: make(map[string]*Node, len(n.children)),
-		key:      n.key,
-		index:    n.index,
-		_type:    n._type,
-		data:     n.data,
-		borders:  n.borders,
-		value:    n.value,
-		dirty:    n.dirty,
+		parent:      n.parent,
+		children:    make(map[string]*Node, len(n.children)),
+		key:         n.key,
+		index:       n.index,
+		_type:       n._type,
+		data:        n.data,
+		origin:      n.origin,
+		borders:     n.borders,
+		value:       n.value,
+		dirty:       n.dirty,
+		constructed: n.constructed,
+	}
+	if n.isContainer() {
+		// cached value of the container refers to the children of the original node
+		node.value = atomic.Value{}
+	}
+	if n.order != nil {
+		node.order = make([]string, len(n.order))
+		copy(node.order, n.order)
 	}
 	for key, value := range n.children {
-		node.children[key] = value.clone()
+		child := value.clone()
+		child.parent = node
+		node.children[key] = child
 	}
 	return node
 }
@@ -196,6 +346,36 @@ func (n *Node) update(_type NodeType, value interface{}) error {
 	if err != nil {
 		return err
 	}
+	nodes := children(value)
+	for _, node := range nodes {
+		if n.isParentOrSelfNode(node) {
+			return errorRequest("attempt to create infinite loop")
+		}
+	}
+	after, err := n.emit(func() Event {
+		return Event{Type: EventSet, Node: n, Path: n.Path(), Old: n.Clone(), New: preview(_type, value)}
+	})
+	if err != nil {
+		return err
+	}
+	if intercepted(nodes...) {
+		// removal of the values from their parents can be cancelled after the node was cleared
+		err = n.atomically(func() error {
+			return n.setValue(_type, value)
+		})
+	} else {
+		err = n.setValue(_type, value)
+	}
+	if err != nil {
+		return err
+	}
+	after()
+	return nil
+}
+
+// setValue sets the value of the node, validated by update
+func (n *Node) setValue(_type NodeType, value interface{}) (err error) {
+	defer n.record()()
 	// update
 	n.mark()
 	n.clear()
@@ -208,15 +388,16 @@ func (n *Node) update(_type NodeType, value interface{}) error {
 			nodes := value.([]*Node)
 			n.children = make(map[string]*Node, len(nodes))
 			for _, node := range nodes {
-				if err = n.appendNode(nil, node); err != nil {
+				if err = n.attachNode(nil, node); err != nil {
 					return err
 				}
 			}
 		case Object:
 			nodes := value.(map[string]*Node)
 			n.children = make(map[string]*Node, len(nodes))
-			for key, node := range nodes {
-				if err = n.appendNode(&key, node); err != nil {
+			for _, key := range sortedKeys(nodes) {
+				key := key
+				if err = n.attachNode(&key, nodes[key]); err != nil {
 					return err
 				}
 			}
@@ -226,6 +407,21 @@ func (n *Node) update(_type NodeType, value interface{}) error {
 	return nil
 }
 
+// children returns the nodes of the value of Array or Object, given to Node.update
+func children(value interface{}) []*Node {
+	switch nodes := value.(type) {
+	case []*Node:
+		return nodes
+	case map[string]*Node:
+		result := make([]*Node, 0, len(nodes))
+		for _, key := range sortedKeys(nodes) {
+			result = append(result, nodes[key])
+		}
+		return result
+	}
+	return nil
+}
+
 // validate method validates stored value, before update
 func (n *Node) validate(_type NodeType, value interface{}) error {
 	if n == nil {
@@ -272,14 +468,24 @@ func (n *Node) remove(value *Node) error {
 	if value.parent != n {
 		return errorRequest("wrong parent")
 	}
+	after, err := value.emit(func() Event {
+		return Event{Type: EventDelete, Node: n, Path: value.Path(), Old: value}
+	})
+	if err != nil {
+		return err
+	}
+	defer after()
+	defer n.record()()
 	n.mark()
 	if n.IsArray() {
 		delete(n.children, strconv.Itoa(*value.index))
 		n.dropindex(*value.index)
 	} else {
-		delete(n.children, *value.key)
+		n.deleteChild(*value.key)
+		delete(n.duplicates, *value.key)
 	}
 	value.parent = nil
+	n.value = atomic.Value{}
 	return nil
 }
 
@@ -295,11 +501,36 @@ func (n *Node) dropindex(index int) {
 	}
 }
 
-// appendNode appends current Node node value with new Node value, by key or index
+// appendNode appends current Node node value with new Node value, by key or index, and notifies the observers
 func (n *Node) appendNode(key *string, value *Node) error {
 	if n.isParentOrSelfNode(value) {
 		return errorRequest("attempt to create infinite loop")
 	}
+	after, err := n.emit(func() Event {
+		index := len(n.children)
+		if value.parent == n {
+			index--
+		}
+		event := Event{Type: EventAppend, Node: n, Path: n.childPath(key, index), New: value}
+		if key != nil && n.children[*key] != value {
+			event.Old = n.children[*key]
+		}
+		return event
+	})
+	if err != nil {
+		return err
+	}
+	if err = n.attachNode(key, value); err != nil {
+		return err
+	}
+	after()
+	return nil
+}
+
+// attachNode appends current Node node value with new Node value, by key or index, without notification of the
+// observers. Value must be checked for the infinite loop before.
+func (n *Node) attachNode(key *string, value *Node) error {
+	defer n.record(value)()
 	if value.parent != nil {
 		if err := value.parent.remove(value); err != nil {
 			return err
@@ -308,22 +539,66 @@ func (n *Node) appendNode(key *string, value *Node) error {
 	value.parent = n
 	value.key = key
 	if key != nil {
-		if old, ok := n.children[*key]; ok {
-			if old != value {
-				if err := n.remove(old); err != nil {
-					return err
-				}
-			}
+		if old, ok := n.children[*key]; ok && old != value {
+			// replaced value keeps the position of the key
+			old.parent = nil
+			delete(n.duplicates, *key)
 		}
-		n.children[*key] = value
+		n.setChild(*key, value)
 	} else {
 		index := len(n.children)
 		value.index = &index
 		n.children[strconv.Itoa(index)] = value
 	}
+	n.value = atomic.Value{}
 	return nil
 }
 
+// insertNode inserts new Node value into current Array node value at index, following values are shifted
+func (n *Node) insertNode(index int, value *Node) error {
+	if n.isParentOrSelfNode(value) {
+		return errorRequest("attempt to create infinite loop")
+	}
+	after, err := n.emit(func() Event {
+		return Event{Type: EventAppend, Node: n, Path: n.childPath(nil, index), New: value}
+	})
+	if err != nil {
+		return err
+	}
+	if err = n.shift(index, value); err != nil {
+		return err
+	}
+	after()
+	return nil
+}
+
+// shift moves the elements of current Array node from the index to the end and puts the value at the index
+func (n *Node) shift(index int, value *Node) error {
+	defer n.record(value)()
+	if value.parent != nil {
+		if err := value.parent.remove(value); err != nil {
+			return err
+		}
+	}
+	for i := len(n.children); i > index; i-- {
+		next := i
+		current := n.children[strconv.Itoa(i-1)]
+		current.index = &next
+		n.children[strconv.Itoa(i)] = current
+	}
+	value.parent = n
+	value.key = nil
+	value.index = &index
+	n.children[strconv.Itoa(index)] = value
+	n.value = atomic.Value{}
+	return nil
+}
+
+// reorder returns the event of the change of the order of elements of current container
+func (n *Node) reorder() Event {
+	return Event{Type: EventSet, Node: n, Path: n.Path(), Old: n.Clone()}
+}
+
 // mark node as dirty, with all parents (up the tree)
 func (n *Node) mark() {
 	node := n
@@ -341,6 +616,51 @@ func (n *Node) clear() {
 		n.children[key].parent = nil
 	}
 	n.children = nil
+	n.order = nil
+	n.duplicates = nil
+}
+
+// setChild sets the child of the Object by key, new key is added to the end of the keys order
+func (n *Node) setChild(key string, value *Node) {
+	if _, ok := n.children[key]; !ok {
+		n.order = append(n.order, key)
+	}
+	n.children[key] = value
+}
+
+// deleteChild removes the child of the Object by key, with its key from the keys order
+func (n *Node) deleteChild(key string) {
+	if _, ok := n.children[key]; !ok {
+		return
+	}
+	delete(n.children, key)
+	for i, current := range n.order {
+		if current == key {
+			n.order = append(n.order[:i], n.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// child returns the element of current node by index (int) for the Array, or by key (string) for the Object
+func (n *Node) child(keyOrIndex interface{}) (*Node, error) {
+	switch value := keyOrIndex.(type) {
+	case string:
+		return n.GetKey(value)
+	case int:
+		return n.GetIndex(value)
+	}
+	return nil, unsupportedType(keyOrIndex)
+}
+
+// indexOfKey returns the position of the key in the keys order, or -1
+func indexOfKey(order []string, key string) int {
+	for i, current := range order {
+		if current == key {
+			return i
+		}
+	}
+	return -1
 }
 
 // isParentOrSelfNode check if current node is the same as given one of parents
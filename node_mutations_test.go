@@ -1480,6 +1480,70 @@ func ExampleNode_Clone() {
 	//
 }
 
+func TestNode_Clone_parents(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [{"b": 1}]}`)))
+	clone := root.Clone()
+	if err := Walk(clone, func(node *Node, depth int) WalkAction {
+		for _, child := range node.Inheritors() {
+			if child.Parent() != node {
+				t.Errorf("wrong parent of %s", child.Path())
+			}
+		}
+		return WalkContinue
+	}); err != nil {
+		t.Fatalf("Walk() unexpected error: %s", err)
+	}
+
+	target := Must(Unmarshal([]byte(`{"c": null}`)))
+	if err := target.MustKey("c").SetNode(root.MustKey("a")); err != nil {
+		t.Fatalf("SetNode() unexpected error: %s", err)
+	}
+	if target.MustKey("c").MustIndex(0).Parent() != target.MustKey("c") {
+		t.Errorf("SetNode() wrong parent of the child")
+	}
+}
+
+func TestNode_Value_changed(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, 2], "b": 3}`)))
+	array := root.MustKey("a")
+	if size := len(array.MustArray()); size != 2 {
+		t.Fatalf("MustArray() wrong size: %d", size)
+	}
+	if err := array.DeleteIndex(0); err != nil {
+		t.Fatalf("DeleteIndex() unexpected error: %s", err)
+	}
+	if value := array.MustArray(); len(value) != 1 || value[0].MustNumeric() != 2 {
+		t.Errorf("MustArray() wrong value after DeleteIndex: %v", value)
+	}
+	if err := array.AppendArray(NumericNode("", 4)); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	if value := array.MustArray(); len(value) != 2 || value[1].MustNumeric() != 4 {
+		t.Errorf("MustArray() wrong value after AppendArray: %v", value)
+	}
+
+	if size := len(root.MustObject()); size != 2 {
+		t.Fatalf("MustObject() wrong size: %d", size)
+	}
+	if err := root.AppendObject("c", NullNode("")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if size := len(root.MustObject()); size != 3 {
+		t.Errorf("MustObject() wrong size after AppendObject: %d", size)
+	}
+	if err := root.DeleteKey("a"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	if _, ok := root.MustObject()["a"]; ok {
+		t.Errorf("MustObject() contains deleted key")
+	}
+
+	clone := root.Clone()
+	if clone.MustObject()["b"] == root.MustKey("b") {
+		t.Errorf("Clone() value refers to the original children")
+	}
+}
+
 func TestNode_SetNode(t *testing.T) {
 	iValue := `{"foo": [{"bar":"baz"}]}`
 	idempotent := Must(Unmarshal([]byte(iValue)))
@@ -1789,3 +1853,228 @@ func TestNode_Set(t *testing.T) {
 		})
 	}
 }
+
+// checkReferences checks the back-references of all nodes: parent, key and index
+func checkReferences(t *testing.T, root *Node) {
+	t.Helper()
+	err := Walk(root, func(node *Node, depth int) WalkAction {
+		for i, child := range node.Inheritors() {
+			if child.Parent() != node {
+				t.Errorf("wrong parent of %s", child.Path())
+			}
+			if node.IsArray() && child.Index() != i {
+				t.Errorf("wrong index of %s: %d", child.Path(), child.Index())
+			}
+			if node.IsObject() && node.children[child.Key()] != child {
+				t.Errorf("wrong key of %s", child.Path())
+			}
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %s", err)
+	}
+}
+
+func TestNode_InsertIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		index    int
+		values   func(root *Node) []*Node
+		expected string
+		err      bool
+	}{
+		{name: "start", index: 0, values: func(*Node) []*Node { return []*Node{NumericNode("", 0)} }, expected: `{"a":[0,1,2,3],"b":{"c": 4}}`},
+		{name: "middle", index: 1, values: func(*Node) []*Node { return []*Node{NumericNode("", 5), NumericNode("", 6)} }, expected: `{"a":[1,5,6,2,3],"b":{"c": 4}}`},
+		{name: "end", index: 3, values: func(*Node) []*Node { return []*Node{NullNode("")} }, expected: `{"a":[1,2,3,null],"b":{"c": 4}}`},
+		{name: "from other parent", index: 1, values: func(root *Node) []*Node { return []*Node{root.MustKey("b").MustKey("c")} }, expected: `{"a":[1,4,2,3],"b":{}}`},
+		{name: "inside the same array", index: 3, values: func(root *Node) []*Node { return []*Node{root.MustKey("a").MustIndex(0)} }, expected: `{"a":[2,3,1],"b":{"c": 4}}`},
+		{name: "out of range", index: 4, values: func(*Node) []*Node { return []*Node{NullNode("")} }, err: true},
+		{name: "negative", index: -1, values: func(*Node) []*Node { return []*Node{NullNode("")} }, err: true},
+		{name: "cycle", index: 0, values: func(root *Node) []*Node { return []*Node{root} }, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": [1, 2, 3], "b": {"c": 4}}`)))
+			err := root.MustKey("a").InsertIndex(test.index, test.values(root)...)
+			if test.err {
+				if err == nil {
+					t.Errorf("InsertIndex() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InsertIndex() unexpected error: %s", err)
+			}
+			if result, _ := Marshal(root); string(result) != test.expected {
+				t.Errorf("InsertIndex() wrong result: %s, expected: %s", result, test.expected)
+			}
+			if !root.MustKey("a").IsDirty() {
+				t.Errorf("InsertIndex() must mark the array as dirty")
+			}
+			checkReferences(t, root)
+		})
+	}
+	if err := NullNode("").InsertIndex(0, NullNode("")); err == nil {
+		t.Errorf("InsertIndex() expected error for not array")
+	}
+}
+
+func TestNode_MoveTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     func(root *Node) *Node
+		parent   func(root *Node) *Node
+		key      interface{}
+		expected string
+		err      bool
+	}{
+		{
+			name:     "object to array",
+			node:     func(root *Node) *Node { return root.MustKey("b").MustKey("c") },
+			parent:   func(root *Node) *Node { return root.MustKey("a") },
+			key:      0,
+			expected: `{"a":[4,1,2],"b":{"d":5}}`,
+		},
+		{
+			name:     "array to object",
+			node:     func(root *Node) *Node { return root.MustKey("a").MustIndex(0) },
+			parent:   func(root *Node) *Node { return root.MustKey("b") },
+			key:      "e",
+			expected: `{"a":[2],"b":{"c":4,"d":5,"e":1}}`,
+		},
+		{
+			name:     "inside the object",
+			node:     func(root *Node) *Node { return root.MustKey("b").MustKey("c") },
+			parent:   func(root *Node) *Node { return root.MustKey("b") },
+			key:      "f",
+			expected: `{"a":[1, 2],"b":{"d":5,"f":4}}`,
+		},
+		{
+			name:     "replace existing key",
+			node:     func(root *Node) *Node { return root.MustKey("a") },
+			parent:   func(root *Node) *Node { return root.MustKey("b") },
+			key:      "d",
+			expected: `{"b":{"c":4,"d":[1, 2]}}`,
+		},
+		{
+			name:   "into itself",
+			node:   func(root *Node) *Node { return root.MustKey("b") },
+			parent: func(root *Node) *Node { return root.MustKey("b") },
+			key:    "x",
+			err:    true,
+		},
+		{
+			name:   "into child",
+			node:   func(root *Node) *Node { return root },
+			parent: func(root *Node) *Node { return root.MustKey("b") },
+			key:    "x",
+			err:    true,
+		},
+		{
+			name:   "wrong key type",
+			node:   func(root *Node) *Node { return root.MustKey("a") },
+			parent: func(root *Node) *Node { return root.MustKey("b") },
+			key:    1.5,
+			err:    true,
+		},
+		{
+			name:   "index for object",
+			node:   func(root *Node) *Node { return root.MustKey("a") },
+			parent: func(root *Node) *Node { return root.MustKey("b") },
+			key:    0,
+			err:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": [1, 2], "b": {"c": 4, "d": 5}}`)))
+			err := test.node(root).MoveTo(test.parent(root), test.key)
+			if test.err {
+				if err == nil {
+					t.Errorf("MoveTo() expected error")
+				}
+				if root.String() != `{"a": [1, 2], "b": {"c": 4, "d": 5}}` {
+					t.Errorf("MoveTo() changed the node on error: %s", root)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MoveTo() unexpected error: %s", err)
+			}
+			if result, _ := Marshal(root); string(result) != test.expected {
+				t.Errorf("MoveTo() wrong result: %s, expected: %s", result, test.expected)
+			}
+			checkReferences(t, root)
+		})
+	}
+}
+
+func TestNode_RenameKey(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": 1, "b": {"c": 2}, "d": 3}`)))
+	if err := root.RenameKey("b", "e"); err != nil {
+		t.Fatalf("RenameKey() unexpected error: %s", err)
+	}
+	if result, _ := Marshal(root); string(result) != `{"a":1,"e":{"c": 2},"d":3}` {
+		t.Errorf("RenameKey() wrong result: %s", result)
+	}
+	if root.MustKey("e").Key() != "e" || root.HasKey("b") || root.MustKey("e").Path() != "$['e']" {
+		t.Errorf("RenameKey() wrong key of the element")
+	}
+	checkReferences(t, root)
+
+	if err := root.RenameKey("a", "a"); err != nil {
+		t.Errorf("RenameKey() unexpected error for the same key: %s", err)
+	}
+	if err := root.RenameKey("a", "d"); err == nil {
+		t.Errorf("RenameKey() expected error for existing key")
+	}
+	if err := root.RenameKey("x", "y"); err == nil {
+		t.Errorf("RenameKey() expected error for missing key")
+	}
+	if err := root.MustKey("a").RenameKey("x", "y"); err == nil {
+		t.Errorf("RenameKey() expected error for not object")
+	}
+
+	root = Must(UnmarshalWithOptions([]byte(`{"a": 1, "a": 2}`), Options{DuplicateKeys: DuplicateKeysAll}))
+	if err := root.RenameKey("a", "b"); err != nil {
+		t.Fatalf("RenameKey() unexpected error: %s", err)
+	}
+	if duplicates := root.Duplicates("b"); len(duplicates) != 2 || duplicates[0].Key() != "b" {
+		t.Errorf("RenameKey() wrong duplicates: %v", duplicates)
+	}
+}
+
+func TestNode_Swap(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, 2, 3], "b": 4, "c": 5}`)))
+	array := root.MustKey("a")
+	if err := array.Swap(0, -1); err != nil {
+		t.Fatalf("Swap() unexpected error: %s", err)
+	}
+	if err := root.Swap("a", "c"); err != nil {
+		t.Fatalf("Swap() unexpected error: %s", err)
+	}
+	if err := root.Swap("b", "b"); err != nil {
+		t.Fatalf("Swap() unexpected error: %s", err)
+	}
+	if result, _ := Marshal(root); string(result) != `{"c":5,"b":4,"a":[3,2,1]}` {
+		t.Errorf("Swap() wrong result: %s", result)
+	}
+	if value := array.MustArray(); value[0].MustNumeric() != 3 {
+		t.Errorf("Swap() wrong value of the array: %v", value)
+	}
+	checkReferences(t, root)
+
+	if err := array.Swap(0, 3); err == nil {
+		t.Errorf("Swap() expected error for wrong index")
+	}
+	if err := array.Swap("a", 0); err == nil {
+		t.Errorf("Swap() expected error for key of array")
+	}
+	if err := root.Swap("a", "x"); err == nil {
+		t.Errorf("Swap() expected error for wrong key")
+	}
+	if err := root.Swap(true, "a"); err == nil {
+		t.Errorf("Swap() expected error for wrong type")
+	}
+}
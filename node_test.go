@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"math"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -748,6 +749,84 @@ func TestNode_Source(t *testing.T) {
 	}
 }
 
+func TestNode_Position(t *testing.T) {
+	root, err := Unmarshal([]byte("{\n  \"ключ\": [1, \"two\"],\n  \"obj\": {\n    \"a\": null\n  }\n}"))
+	if err != nil {
+		t.Fatalf("Error on Unmarshal(): %s", err.Error())
+	}
+	tests := []struct {
+		name     string
+		node     *Node
+		expected Position
+	}{
+		{name: "root", node: root, expected: Position{Offset: 0, End: 58, Line: 1, Column: 1, EndLine: 6, EndColumn: 2}},
+		{name: "array", node: root.MustKey("ключ"), expected: Position{Offset: 16, End: 26, Line: 2, Column: 11, EndLine: 2, EndColumn: 21}},
+		{name: "string", node: root.MustKey("ключ").MustIndex(1), expected: Position{Offset: 20, End: 25, Line: 2, Column: 15, EndLine: 2, EndColumn: 20}},
+		{name: "null", node: root.MustKey("obj").MustKey("a"), expected: Position{Offset: 48, End: 52, Line: 4, Column: 10, EndLine: 4, EndColumn: 14}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.node.Position()
+			if err != nil {
+				t.Fatalf("Position() unexpected error: %s", err)
+			}
+			if result != test.expected {
+				t.Errorf("Position() wrong result: %+v, expected: %+v", result, test.expected)
+			}
+		})
+	}
+
+	if _, err = NumericNode("", 1).Position(); err == nil {
+		t.Errorf("Position() expected error for a constructed node")
+	}
+	literal, err := NumericLiteralNode("", "12345678901234567890")
+	if err != nil {
+		t.Fatalf("NumericLiteralNode() unexpected error: %s", err)
+	}
+	for _, constructed := range []*Node{Int64Node("", 1), Uint64Node("", 1), BigIntNode("", big.NewInt(1)), literal, literal.Clone()} {
+		if _, err = constructed.Position(); err == nil {
+			t.Errorf("Position() expected error for a literal node %s", constructed)
+		}
+	}
+	node := root.MustKey("obj").MustKey("a")
+	if err = node.SetBool(true); err != nil {
+		t.Fatalf("SetBool() unexpected error: %s", err)
+	}
+	if _, err = node.Position(); err == nil {
+		t.Errorf("Position() expected error for an updated node")
+	}
+	if _, err = (*Node)(nil).Position(); err == nil {
+		t.Errorf("Position() expected error for (*Node)(nil)")
+	}
+}
+
+func TestNode_Keys_order(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"c": 1, "a": 2, "b": 3, "a": 4}`)))
+	if keys := root.Keys(); !sliceEqual(keys, []string{"c", "a", "b"}) {
+		t.Errorf("Keys() wrong order: %v", keys)
+	}
+	if err := root.AppendObject("0", NullNode("")); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if err := root.DeleteKey("a"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	if keys := root.Keys(); !sliceEqual(keys, []string{"c", "b", "0"}) {
+		t.Errorf("Keys() wrong order: %v", keys)
+	}
+	inheritors := root.Inheritors()
+	if len(inheritors) != 3 || inheritors[0].Key() != "c" || inheritors[1].Key() != "b" || inheritors[2].Key() != "0" {
+		t.Errorf("Inheritors() wrong order: %v", Paths(inheritors))
+	}
+	if keys := root.Clone().Keys(); !sliceEqual(keys, []string{"c", "b", "0"}) {
+		t.Errorf("Clone() wrong order: %v", keys)
+	}
+	object := ObjectNode("", map[string]*Node{"b": NullNode(""), "a": NullNode("")})
+	if keys := object.Keys(); !sliceEqual(keys, []string{"a", "b"}) {
+		t.Errorf("ObjectNode() wrong order: %v", keys)
+	}
+}
+
 func TestNode_String(t *testing.T) {
 	root, err := Unmarshal([]byte(`{"foo":true,"bar":null}`))
 	if err != nil {
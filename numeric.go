@@ -0,0 +1,198 @@
+package ajson
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// This file contains exact accessors and constructors of Numeric nodes. Unlike GetNumeric, accessors parse the
+// original literal of the node, so integers above 2^53 and long decimals don't lose their precision.
+
+// Int64Node is constructor for Node with a Numeric value, that keeps the exact integer
+func Int64Node(key string, value int64) *Node {
+	return literalNode(key, strconv.FormatInt(value, 10))
+}
+
+// Uint64Node is constructor for Node with a Numeric value, that keeps the exact unsigned integer
+func Uint64Node(key string, value uint64) *Node {
+	return literalNode(key, strconv.FormatUint(value, 10))
+}
+
+// BigIntNode is constructor for Node with a Numeric value, that keeps the exact integer; nil value produces Null node
+func BigIntNode(key string, value *big.Int) *Node {
+	if value == nil {
+		return NullNode(key)
+	}
+	return literalNode(key, value.String())
+}
+
+// BigFloatNode is constructor for Node with a Numeric value, that keeps all the digits of the value, according to its
+// precision; nil value produces Null node
+func BigFloatNode(key string, value *big.Float) *Node {
+	if value == nil {
+		return NullNode(key)
+	}
+	if value.IsInf() {
+		return NumericNode(key, math.Inf(value.Sign()))
+	}
+	return literalNode(key, value.Text('g', -1))
+}
+
+// NumericLiteralNode is constructor for Node with a Numeric value, given as a JSON number literal, e.g.
+// "12345678901234567890" or "0.10000000000000000001". Literal will be kept as is by Marshal.
+func NumericLiteralNode(key string, literal string) (*Node, error) {
+	if literal == "" || !strictNumeric([]byte(literal)) {
+		return nil, errorRequest("wrong numeric literal: '%s'", literal)
+	}
+	return literalNode(key, literal), nil
+}
+
+// literalNode creates a parsed Numeric node with its own source, so the literal is used by Marshal and accessors
+func literalNode(key string, literal string) *Node {
+	data := []byte(literal)
+	return &Node{
+		_type:       Numeric,
+		key:         &key,
+		data:        &data,
+		borders:     [2]int{0, len(data)},
+		constructed: true,
+	}
+}
+
+// GetInt64 returns int64, if current type is Numeric and its value is an integer in the int64 range, else: WrongType
+// error
+func (n *Node) GetInt64() (int64, error) {
+	value, err := n.GetBigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsInt64() {
+		return 0, errorOverflow(value.String(), "int64")
+	}
+	return value.Int64(), nil
+}
+
+// GetUint64 returns uint64, if current type is Numeric and its value is an integer in the uint64 range, else: WrongType
+// error
+func (n *Node) GetUint64() (uint64, error) {
+	value, err := n.GetBigInt()
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsUint64() {
+		return 0, errorOverflow(value.String(), "uint64")
+	}
+	return value.Uint64(), nil
+}
+
+// GetBigInt returns *big.Int, if current type is Numeric and its value is an integer, else: WrongType error
+func (n *Node) GetBigInt() (*big.Int, error) {
+	value, err := n.GetBigRat()
+	if err != nil {
+		return nil, err
+	}
+	if !value.IsInt() {
+		return nil, errorOverflow(value.FloatString(10), "integer")
+	}
+	return new(big.Int).Set(value.Num()), nil
+}
+
+// GetBigRat returns *big.Rat with the exact value of the literal, if current type is Numeric, else: WrongType error
+func (n *Node) GetBigRat() (*big.Rat, error) {
+	literal, err := n.literal()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := new(big.Rat).SetString(literal)
+	if !ok {
+		return nil, errorOverflow(literal, "big.Rat")
+	}
+	return value, nil
+}
+
+// GetBigFloat returns *big.Float, if current type is Numeric, else: WrongType error. Precision of the result is enough
+// to keep all the digits of the literal, but not less than the precision of float64.
+func (n *Node) GetBigFloat() (*big.Float, error) {
+	literal, err := n.literal()
+	if err != nil {
+		return nil, err
+	}
+	precision := uint(math.Ceil(float64(len(literal)) * math.Log2(10)))
+	if precision < 53 {
+		precision = 53
+	}
+	value, _, err := big.ParseFloat(literal, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return nil, errorOverflow(literal, "big.Float")
+	}
+	return value, nil
+}
+
+// MustInt64 returns int64, if current type is Numeric and its value is an integer in the int64 range, else: panic if
+// error happened
+func (n *Node) MustInt64() (value int64) {
+	value, err := n.GetInt64()
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// MustUint64 returns uint64, if current type is Numeric and its value is an integer in the uint64 range, else: panic
+// if error happened
+func (n *Node) MustUint64() (value uint64) {
+	value, err := n.GetUint64()
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// MustBigInt returns *big.Int, if current type is Numeric and its value is an integer, else: panic if error happened
+func (n *Node) MustBigInt() (value *big.Int) {
+	value, err := n.GetBigInt()
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// MustBigRat returns *big.Rat, if current type is Numeric, else: panic if error happened
+func (n *Node) MustBigRat() (value *big.Rat) {
+	value, err := n.GetBigRat()
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// MustBigFloat returns *big.Float, if current type is Numeric, else: panic if error happened
+func (n *Node) MustBigFloat() (value *big.Float) {
+	value, err := n.GetBigFloat()
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// literal returns the source of the Numeric node, or its formatted value, if node was changed
+func (n *Node) literal() (string, error) {
+	if n == nil {
+		return "", errorUnparsed()
+	}
+	if n._type != Numeric {
+		return "", errorType()
+	}
+	if source := n.Source(); source != nil {
+		return string(source), nil
+	}
+	value, err := n.GetNumeric()
+	if err != nil {
+		return "", err
+	}
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return "", errorOverflow(strconv.FormatFloat(value, 'g', -1, 64), "finite number")
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64), nil
+}
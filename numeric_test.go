@@ -0,0 +1,175 @@
+package ajson
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNode_GetInt64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+		err      bool
+		message  string
+	}{
+		{name: "zero", input: `0`, expected: 0},
+		{name: "above 2^53", input: `9007199254740993`, expected: 9007199254740993},
+		{name: "max", input: `9223372036854775807`, expected: math.MaxInt64},
+		{name: "min", input: `-9223372036854775808`, expected: math.MinInt64},
+		{name: "exponent", input: `1.5e3`, expected: 1500},
+		{name: "integer float", input: `12.000`, expected: 12},
+		{name: "overflow", input: `9223372036854775808`, err: true, message: "wrong type of Node: value 9223372036854775808 can't be represented as int64"},
+		{name: "fraction", input: `1.5`, err: true, message: "wrong type of Node: value 1.5000000000 can't be represented as integer"},
+		{name: "huge exponent", input: `1e999999999`, err: true},
+		{name: "string", input: `"1"`, err: true, message: "wrong type of Node"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := Must(Unmarshal([]byte(test.input))).GetInt64()
+			if test.err {
+				if err == nil {
+					t.Errorf("GetInt64() expected error, got: %d", value)
+				} else if current, ok := err.(Error); !ok || current.Type != WrongType {
+					t.Errorf("GetInt64() wrong error: %#v", err)
+				} else if test.message != "" && err.Error() != test.message {
+					t.Errorf("GetInt64() wrong error message: %s, expected: %s", err, test.message)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetInt64() unexpected error: %s", err)
+			}
+			if value != test.expected {
+				t.Errorf("GetInt64() wrong value: %d, expected: %d", value, test.expected)
+			}
+		})
+	}
+}
+
+func TestNode_GetUint64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected uint64
+		err      bool
+	}{
+		{name: "max", input: `18446744073709551615`, expected: math.MaxUint64},
+		{name: "overflow", input: `18446744073709551616`, err: true},
+		{name: "negative", input: `-1`, err: true},
+		{name: "null", input: `null`, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, err := Must(Unmarshal([]byte(test.input))).GetUint64()
+			if test.err {
+				if err == nil {
+					t.Errorf("GetUint64() expected error, got: %d", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetUint64() unexpected error: %s", err)
+			}
+			if value != test.expected {
+				t.Errorf("GetUint64() wrong value: %d, expected: %d", value, test.expected)
+			}
+		})
+	}
+}
+
+func TestNode_GetBigInt(t *testing.T) {
+	root := Must(Unmarshal([]byte(`[123456789012345678901234567890, -1e30, 0.5]`)))
+	if value := root.MustIndex(0).MustBigInt().String(); value != "123456789012345678901234567890" {
+		t.Errorf("GetBigInt() wrong value: %s", value)
+	}
+	if value := root.MustIndex(1).MustBigInt().String(); value != "-1000000000000000000000000000000" {
+		t.Errorf("GetBigInt() wrong value: %s", value)
+	}
+	if _, err := root.MustIndex(2).GetBigInt(); err == nil {
+		t.Errorf("GetBigInt() expected error")
+	}
+	if _, err := (*Node)(nil).GetBigInt(); err == nil {
+		t.Errorf("GetBigInt() expected error for (*Node)(nil)")
+	}
+}
+
+func TestNode_GetBigFloat(t *testing.T) {
+	root := Must(Unmarshal([]byte(`[0.10000000000000000000000001, 1]`)))
+	value := root.MustIndex(0).MustBigFloat()
+	if text := value.Text('g', 27); text != "0.10000000000000000000000001" {
+		t.Errorf("GetBigFloat() wrong value: %s", text)
+	}
+	if value = root.MustIndex(1).MustBigFloat(); value.Prec() < 53 {
+		t.Errorf("GetBigFloat() wrong precision: %d", value.Prec())
+	}
+	if rat := root.MustIndex(0).MustBigRat(); rat.FloatString(26) != "0.10000000000000000000000001" {
+		t.Errorf("GetBigRat() wrong value: %s", rat.FloatString(26))
+	}
+}
+
+func TestNode_GetInt64_updated(t *testing.T) {
+	node := Must(Unmarshal([]byte(`1`)))
+	if err := node.SetNumeric(1e15); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	if value := node.MustInt64(); value != 1e15 {
+		t.Errorf("GetInt64() wrong value: %d", value)
+	}
+	if _, err := NumericNode("", 1e20).GetInt64(); err == nil {
+		t.Errorf("GetInt64() expected error")
+	}
+	if _, err := NumericNode("", math.Inf(1)).GetBigInt(); err == nil {
+		t.Errorf("GetBigInt() expected error")
+	}
+}
+
+func TestNumericLiteralNode(t *testing.T) {
+	big1, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	big2, _, _ := big.ParseFloat("3.14159265358979323846264338327950288", 10, 128, big.ToNearestEven)
+	tests := []struct {
+		name     string
+		node     *Node
+		expected string
+	}{
+		{name: "Int64Node", node: Int64Node("", math.MaxInt64), expected: "9223372036854775807"},
+		{name: "Uint64Node", node: Uint64Node("", math.MaxUint64), expected: "18446744073709551615"},
+		{name: "BigIntNode", node: BigIntNode("", big1), expected: "123456789012345678901234567890"},
+		{name: "BigIntNode nil", node: BigIntNode("", nil), expected: "null"},
+		{name: "BigFloatNode", node: BigFloatNode("", big2), expected: big2.Text('g', -1)},
+		{name: "NumericLiteralNode", node: Must(NumericLiteralNode("", "0.10000000000000000001")), expected: "0.10000000000000000001"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := ArrayNode("", []*Node{test.node})
+			result, err := Marshal(root)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != "["+test.expected+"]" {
+				t.Errorf("Marshal() wrong result: %s", result)
+			}
+		})
+	}
+	if value := Int64Node("", math.MaxInt64).MustInt64(); value != math.MaxInt64 {
+		t.Errorf("GetInt64() wrong value: %d", value)
+	}
+	for _, literal := range []string{"", "01", "1.", "+1", "0x10", "NaN", "1 "} {
+		if _, err := NumericLiteralNode("", literal); err == nil {
+			t.Errorf("NumericLiteralNode(%q) expected error", literal)
+		}
+	}
+}
+
+func ExampleNode_GetInt64() {
+	root := Must(Unmarshal([]byte(`{"id": 9007199254740993, "big": 18446744073709551616}`)))
+	id, err := root.MustKey("id").GetInt64()
+	fmt.Println(id, err)
+	_, err = root.MustKey("big").GetUint64()
+	fmt.Println(err.(Error).Message)
+	// Output:
+	// 9007199254740993 <nil>
+	// value 18446744073709551616 can't be represented as uint64
+}
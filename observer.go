@@ -0,0 +1,247 @@
+package ajson
+
+import (
+	"strconv"
+)
+
+// EventType is the kind of the change of the document, reported to the observers
+type EventType int
+
+const (
+	// EventSet is the replacement of the value of the node (Node.SetString, Node.SetNode and others), or the change of
+	// the order of its elements (Node.RenameKey and Node.Swap)
+	EventSet EventType = iota
+	// EventAppend is the addition of the element into the container (Node.AppendArray, Node.AppendObject,
+	// Node.InsertIndex and others)
+	EventAppend
+	// EventDelete is the removal of the element from the container (Node.DeleteKey, Node.DeleteIndex and others)
+	EventDelete
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case EventSet:
+		return "set"
+	case EventAppend:
+		return "append"
+	case EventDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// Event describes the change of the document. The same event is given to the interceptors before the change and to
+// the observers after it.
+type Event struct {
+	Type EventType
+	// Node is the changed node: the node itself for EventSet, or the container for EventAppend and EventDelete
+	Node *Node
+	// Path is JSONPath of the changed node for EventSet, or of the added or removed element of the container
+	Path string
+	// Old is the detached copy of the node before EventSet, the removed element for EventDelete, or the element,
+	// replaced by the same key of the Object, for EventAppend
+	Old *Node
+	// New is the new value for EventSet, or the added element for EventAppend. It's nil for EventDelete and for the
+	// changes of the order of elements.
+	New *Node
+}
+
+// observer is the callback, registered on the node: before is called prior to the change and can cancel it, after
+// is called when the change is done. Observer with commands gets only the changes of the nodes, found by JSONPath.
+type observer struct {
+	commands []string
+	before   func(event Event) error
+	after    func(event Event)
+}
+
+// Observe registers the callback, which is called after every change of the node and its descendants. Result is the
+// function, that unregisters the callback.
+//
+// Callbacks are kept in the node, so they follow the node, when it's moved to another place in the tree. Changes,
+// reverted or redone by the Transaction, are reported as EventSet of the restored nodes, without Old value.
+func (n *Node) Observe(fn func(event Event)) func() {
+	return n.observe(&observer{after: fn})
+}
+
+// Intercept registers the callback, which is called before every change of the node and its descendants. Error of
+// the callback cancels the change, the mutation method returns this error and the document is left unchanged: values
+// of Node.AppendArray and Node.InsertIndex, added before the cancelled one, and the cleared value of Node.SetArray and
+// Node.SetObject are reverted. Observers are not notified about the failed changes. Result is the function, that
+// unregisters the callback.
+//
+// Callbacks must not change the document. Transaction.Undo, Transaction.Redo and Transaction.Rollback can't be
+// cancelled.
+//
+// Example:
+//
+// 	cancel := root.Intercept(func(event ajson.Event) error {
+// 		if event.Type == ajson.EventDelete && event.Old.Key() == "id" {
+// 			return fmt.Errorf("%s is read-only", event.Path)
+// 		}
+// 		return nil
+// 	})
+// 	defer cancel()
+func (n *Node) Intercept(fn func(event Event) error) func() {
+	return n.observe(&observer{before: fn})
+}
+
+// ObservePath registers the callback like Observe, but only for the changes of the nodes, found by JSONPath from the
+// current node, and their descendants. Nodes are found at the moment of the change.
+//
+// Example:
+//
+// 	cancel, err := root.ObservePath("$..price", func(event ajson.Event) {
+// 		fmt.Printf("%s: %s\n", event.Path, event.New)
+// 	})
+func (n *Node) ObservePath(path string, fn func(event Event)) (func(), error) {
+	commands, err := ParseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return n.observe(&observer{commands: commands, after: fn}), nil
+}
+
+// InterceptPath registers the callback like Intercept, but only for the changes of the nodes, found by JSONPath from
+// the current node, and their descendants. Nodes are found at the moment of the change.
+func (n *Node) InterceptPath(path string, fn func(event Event) error) (func(), error) {
+	commands, err := ParseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return n.observe(&observer{commands: commands, before: fn}), nil
+}
+
+// observe adds the observer to the node and returns the function, that removes it
+func (n *Node) observe(current *observer) func() {
+	if n == nil {
+		return func() {}
+	}
+	n.observers = append(n.observers, current)
+	return func() {
+		for i, value := range n.observers {
+			if value == current {
+				n.observers = append(n.observers[:i:i], n.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// hooks returns the observers of the node and its parents, interested in the change of the node
+func (n *Node) hooks() (result []*observer) {
+	for node := n; node != nil; node = node.parent {
+		for _, current := range node.observers {
+			if current.commands == nil || n.foundBy(node, current.commands) {
+				result = append(result, current)
+			}
+		}
+	}
+	return result
+}
+
+// intercepted returns true if the change of any of the nodes, e.g. the removal from their parents, can be cancelled
+// by any interceptor
+func intercepted(nodes ...*Node) bool {
+	for _, node := range nodes {
+		for current := node; current != nil; current = current.parent {
+			for _, value := range current.observers {
+				if value.before != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// foundBy returns true if the node or one of its parents is found by JSONPath commands from the given node
+func (n *Node) foundBy(node *Node, commands []string) bool {
+	found, err := ApplyJSONPath(node, commands)
+	if err != nil {
+		return false
+	}
+	for current := n; current != nil; current = current.parent {
+		for _, value := range found {
+			if value == current {
+				return true
+			}
+		}
+		if current == node {
+			break
+		}
+	}
+	return false
+}
+
+// emit calls the interceptors of the change of the node, and returns the function, which calls the observers and
+// must be called after the change. Event is built only if the change is observed.
+func (n *Node) emit(event func() Event) (func(), error) {
+	hooks := n.hooks()
+	if len(hooks) == 0 {
+		return func() {}, nil
+	}
+	current := event()
+	for _, hook := range hooks {
+		if hook.before != nil {
+			if err := hook.before(current); err != nil {
+				return nil, err
+			}
+		}
+	}
+	t := n.root().transaction
+	return func() {
+		for _, hook := range hooks {
+			if hook.after != nil {
+				if t != nil {
+					t.reported = true
+				}
+				hook.after(current)
+			}
+		}
+	}, nil
+}
+
+// notify calls the observers after the node was restored by the Transaction
+func (n *Node) notify() {
+	for _, hook := range n.hooks() {
+		if hook.after != nil {
+			hook.after(Event{Type: EventSet, Node: n, Path: n.Path(), New: n})
+		}
+	}
+}
+
+// preview returns the detached node with the value, given to Node.update
+func preview(_type NodeType, value interface{}) *Node {
+	switch _type {
+	case Numeric:
+		return NumericNode("", value.(float64))
+	case String:
+		return StringNode("", value.(string))
+	case Bool:
+		return BoolNode("", value.(bool))
+	case Array:
+		nodes, _ := value.([]*Node)
+		clones := make([]*Node, 0, len(nodes))
+		for _, node := range nodes {
+			clones = append(clones, node.Clone())
+		}
+		return ArrayNode("", clones)
+	case Object:
+		nodes, _ := value.(map[string]*Node)
+		clones := make(map[string]*Node, len(nodes))
+		for key, node := range nodes {
+			clones[key] = node.Clone()
+		}
+		return ObjectNode("", clones)
+	}
+	return NullNode("")
+}
+
+// childPath returns JSONPath of the element of the container by key or index
+func (n *Node) childPath(key *string, index int) string {
+	if key != nil {
+		return n.Path() + "['" + *key + "']"
+	}
+	return n.Path() + "[" + strconv.Itoa(index) + "]"
+}
@@ -0,0 +1,433 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+// eventString returns the short description of the event for tests
+func eventString(event Event) string {
+	return fmt.Sprintf("%s %s %s %s", event.Type, event.Path, marshalString(event.Old), marshalString(event.New))
+}
+
+// marshalString returns the marshaled node, or "-" for nil
+func marshalString(node *Node) string {
+	if node == nil {
+		return "-"
+	}
+	result, err := Marshal(node)
+	if err != nil {
+		return err.Error()
+	}
+	return string(compact(result))
+}
+
+func TestNode_Observe(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   func(root *Node) error
+		expected []string
+		result   string
+	}{
+		{
+			name:     "SetString",
+			action:   func(root *Node) error { return root.MustKey("a").MustIndex(0).SetString("x") },
+			expected: []string{`set $['a'][0] 1 "x"`},
+			result:   `{"a":["x",2],"b":{"c":null}}`,
+		},
+		{
+			name:     "SetArray",
+			action:   func(root *Node) error { return root.MustKey("b").SetArray([]*Node{NumericNode("", 1)}) },
+			expected: []string{`set $['b'] {"c":null} [1]`},
+			result:   `{"a":[1,2],"b":[1]}`,
+		},
+		{
+			name:     "SetNode",
+			action:   func(root *Node) error { return root.MustKey("b").SetNode(Must(Unmarshal([]byte(`{"d":1}`)))) },
+			expected: []string{`set $['b'] {"c":null} {"d":1}`},
+			result:   `{"a":[1,2],"b":{"d":1}}`,
+		},
+		{
+			name:     "AppendArray",
+			action:   func(root *Node) error { return root.MustKey("a").AppendArray(NumericNode("", 3), NullNode("")) },
+			expected: []string{`append $['a'][2] - 3`, `append $['a'][3] - null`},
+			result:   `{"a":[1,2,3,null],"b":{"c":null}}`,
+		},
+		{
+			name:     "AppendObject replace",
+			action:   func(root *Node) error { return root.MustKey("b").AppendObject("c", BoolNode("", true)) },
+			expected: []string{`append $['b']['c'] null true`},
+			result:   `{"a":[1,2],"b":{"c":true}}`,
+		},
+		{
+			name:     "InsertIndex",
+			action:   func(root *Node) error { return root.MustKey("a").InsertIndex(1, StringNode("", "x")) },
+			expected: []string{`append $['a'][1] - "x"`},
+			result:   `{"a":[1,"x",2],"b":{"c":null}}`,
+		},
+		{
+			name:     "DeleteIndex",
+			action:   func(root *Node) error { return root.MustKey("a").DeleteIndex(0) },
+			expected: []string{`delete $['a'][0] 1 -`},
+			result:   `{"a":[2],"b":{"c":null}}`,
+		},
+		{
+			name:     "MoveTo",
+			action:   func(root *Node) error { return root.MustKey("a").MustIndex(1).MoveTo(root.MustKey("b"), "d") },
+			expected: []string{`delete $['a'][1] 2 -`, `append $['b']['d'] - 2`},
+			result:   `{"a":[1],"b":{"c":null,"d":2}}`,
+		},
+		{
+			name:     "RenameKey",
+			action:   func(root *Node) error { return root.RenameKey("b", "d") },
+			expected: []string{`set $ {"a":[1,2],"b":{"c":null}} -`},
+			result:   `{"a":[1,2],"d":{"c":null}}`,
+		},
+		{
+			name:     "Swap",
+			action:   func(root *Node) error { return root.MustKey("a").Swap(0, 1) },
+			expected: []string{`set $['a'] [1,2] -`},
+			result:   `{"a":[2,1],"b":{"c":null}}`,
+		},
+		{
+			name:     "UnmarshalJSON",
+			action:   func(root *Node) error { return root.MustKey("b").UnmarshalJSON([]byte(`[true]`)) },
+			expected: []string{`set $['b'] {"c":null} [true]`},
+			result:   `{"a":[1,2],"b":[true]}`,
+		},
+		{
+			name:     "Walk",
+			action:   func(root *Node) error { return Walk(root, walkNull) },
+			expected: []string{`delete $['b']['c'] null -`},
+			result:   `{"a":[1,2],"b":{}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": [1, 2], "b": {"c": null}}`)))
+			result := make([]string, 0)
+			root.Observe(func(event Event) {
+				result = append(result, eventString(event))
+			})
+			if err := test.action(root); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !sliceEqual(result, test.expected) {
+				t.Errorf("wrong events:\n%s\nexpected:\n%s", sliceString(result), sliceString(test.expected))
+			}
+			if value := marshalString(root); value != test.result {
+				t.Errorf("wrong result: %s", value)
+			}
+			checkReferences(t, root)
+		})
+	}
+}
+
+// walkNull removes Null nodes
+func walkNull(node *Node, _ int) WalkAction {
+	if node.IsNull() {
+		return WalkDelete
+	}
+	return WalkContinue
+}
+
+func TestNode_Intercept(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(root *Node) error
+	}{
+		{name: "SetNumeric", action: func(root *Node) error { return root.MustKey("a").MustIndex(0).SetNumeric(3) }},
+		{name: "SetNode", action: func(root *Node) error { return root.MustKey("b").SetNode(NullNode("")) }},
+		{name: "AppendArray", action: func(root *Node) error { return root.MustKey("a").AppendArray(NullNode("")) }},
+		{name: "AppendObject", action: func(root *Node) error { return root.MustKey("b").AppendObject("d", NullNode("")) }},
+		{name: "InsertIndex", action: func(root *Node) error { return root.MustKey("a").InsertIndex(0, NullNode("")) }},
+		{name: "DeleteKey", action: func(root *Node) error { return root.MustKey("b").DeleteKey("c") }},
+		{name: "RenameKey", action: func(root *Node) error { return root.RenameKey("a", "d") }},
+		{name: "Swap", action: func(root *Node) error { return root.Swap("a", "b") }},
+		{name: "UnmarshalJSON", action: func(root *Node) error { return root.MustKey("b").UnmarshalJSON([]byte(`1`)) }},
+		{name: "Scan", action: func(root *Node) error { return root.MustKey("b").Scan(nil) }},
+		{name: "MoveTo", action: func(root *Node) error { return root.MustKey("b").MustKey("c").MoveTo(root.MustKey("a"), 0) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": [1, 2], "b": {"c": null}}`)))
+			observed := 0
+			root.Observe(func(Event) { observed++ })
+			root.Intercept(func(event Event) error {
+				return fmt.Errorf("read-only: %s", event.Path)
+			})
+			err := test.action(root)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if value := marshalString(root); value != `{"a":[1,2],"b":{"c":null}}` {
+				t.Errorf("document is changed: %s", value)
+			}
+			if root.IsDirty() {
+				t.Errorf("document is marked as dirty")
+			}
+			if observed != 0 {
+				t.Errorf("observer is called %d times", observed)
+			}
+			checkReferences(t, root)
+		})
+	}
+}
+
+func TestNode_Intercept_source(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(target, x *Node) error
+	}{
+		{name: "SetArray", action: func(target, x *Node) error { return target.MustKey("arr").SetArray([]*Node{x}) }},
+		{name: "SetObject", action: func(target, x *Node) error {
+			return target.MustKey("obj").SetObject(map[string]*Node{"x": x, "y": NullNode("")})
+		}},
+		{name: "AppendObject", action: func(target, x *Node) error { return target.MustKey("obj").AppendObject("y", x) }},
+		{name: "AppendArray", action: func(target, x *Node) error { return target.MustKey("arr").AppendArray(x) }},
+		{name: "InsertIndex", action: func(target, x *Node) error { return target.MustKey("arr").InsertIndex(0, x) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := Must(Unmarshal([]byte(`{"x": 1}`)))
+			target := Must(Unmarshal([]byte(`{"arr": [1, 2], "obj": {"a": 1}}`)))
+			source.Intercept(func(event Event) error {
+				if event.Type == EventDelete {
+					return fmt.Errorf("veto")
+				}
+				return nil
+			})
+			observed := 0
+			target.Observe(func(Event) { observed++ })
+			if err := test.action(target, source.MustKey("x")); err == nil {
+				t.Fatalf("expected error")
+			}
+			if value := target.String(); value != `{"arr": [1, 2], "obj": {"a": 1}}` || target.IsDirty() {
+				t.Errorf("document is changed: %s", value)
+			}
+			if value := source.String(); value != `{"x": 1}` {
+				t.Errorf("source is changed: %s", value)
+			}
+			if observed != 0 {
+				t.Errorf("observer is called %d times", observed)
+			}
+			checkReferences(t, target)
+			checkReferences(t, source)
+		})
+	}
+}
+
+func TestNode_Observe_loop(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": [1]}}`)))
+	observed := 0
+	root.Observe(func(Event) { observed++ })
+	if err := root.MustKey("a").AppendObject("z", root); err == nil {
+		t.Errorf("AppendObject() expected error")
+	}
+	if err := root.MustKey("a").MustKey("b").InsertIndex(0, root.MustKey("a")); err == nil {
+		t.Errorf("InsertIndex() expected error")
+	}
+	if err := root.MustKey("a").MustKey("b").SetArray([]*Node{root}); err == nil {
+		t.Errorf("SetArray() expected error")
+	}
+	if value := root.String(); value != `{"a": {"b": [1]}}` || observed != 0 {
+		t.Errorf("document is changed: %s, observer is called %d times", value, observed)
+	}
+}
+
+func TestNode_Intercept_partial(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, 2, 3]}`)))
+	cancel := root.Intercept(func(event Event) error {
+		if event.New != nil && event.New.IsNull() {
+			return fmt.Errorf("null is not allowed")
+		}
+		return nil
+	})
+	if err := root.MustKey("a").AppendArray(NumericNode("", 4), NullNode(""), NumericNode("", 5)); err == nil {
+		t.Errorf("AppendArray() expected error")
+	}
+	if err := root.MustKey("a").InsertIndex(1, NumericNode("", 4), NullNode("")); err == nil {
+		t.Errorf("InsertIndex() expected error")
+	}
+	if value := root.String(); value != `{"a": [1, 2, 3]}` || root.IsDirty() {
+		t.Errorf("wrong result: %s", value)
+	}
+	checkReferences(t, root)
+	tx, err := root.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	if err = root.MustKey("a").AppendArray(NumericNode("", 4), NullNode("")); err == nil {
+		t.Errorf("AppendArray() expected error")
+	}
+	if value := root.String(); value != `{"a": [1, 2, 3]}` || tx.CanUndo() {
+		t.Errorf("wrong result in transaction: %s", value)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Commit() unexpected error: %s", err)
+	}
+	if err := root.MustKey("a").MustIndex(0).SetNull(); err == nil {
+		t.Errorf("SetNull() expected error")
+	}
+	cancel()
+	if err := root.MustKey("a").MustIndex(0).SetNull(); err != nil {
+		t.Errorf("SetNull() unexpected error: %s", err)
+	}
+}
+
+func TestNode_ObservePath(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"store": {"book": [{"price": 10}, {"price": 20}], "name": "shop"}}`)))
+	result := make([]string, 0)
+	_, err := root.ObservePath("$..book[?(@.price > 15)]", func(event Event) {
+		result = append(result, eventString(event))
+	})
+	if err != nil {
+		t.Fatalf("ObservePath() unexpected error: %s", err)
+	}
+	book := root.MustKey("store").MustKey("book")
+	actions := []func() error{
+		func() error { return book.MustIndex(0).MustKey("price").SetNumeric(11) },
+		func() error { return book.MustIndex(1).MustKey("price").SetNumeric(21) },
+		func() error { return book.MustIndex(1).AppendObject("title", StringNode("", "x")) },
+		func() error { return root.MustKey("store").MustKey("name").SetString("market") },
+		func() error { return book.MustIndex(0).MustKey("price").SetNumeric(30) },
+		func() error { return book.MustIndex(0).MustKey("price").SetNumeric(1) },
+	}
+	for i, action := range actions {
+		if err = action(); err != nil {
+			t.Fatalf("action %d unexpected error: %s", i, err)
+		}
+	}
+	expected := []string{
+		`set $['store']['book'][1]['price'] 20 21`,
+		`append $['store']['book'][1]['title'] - "x"`,
+		`set $['store']['book'][0]['price'] 30 1`,
+	}
+	if !sliceEqual(result, expected) {
+		t.Errorf("wrong events:\n%s\nexpected:\n%s", sliceString(result), sliceString(expected))
+	}
+
+	if _, err = root.ObservePath("$[", func(Event) {}); err == nil {
+		t.Errorf("ObservePath() expected error")
+	}
+	if _, err = root.InterceptPath("$[", func(Event) error { return nil }); err == nil {
+		t.Errorf("InterceptPath() expected error")
+	}
+}
+
+func TestNode_InterceptPath(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"id": 1, "data": {"id": 2, "value": 3}}`)))
+	_, err := root.InterceptPath("$.id", func(event Event) error {
+		return fmt.Errorf("%s is read-only", event.Path)
+	})
+	if err != nil {
+		t.Fatalf("InterceptPath() unexpected error: %s", err)
+	}
+	if err = root.MustKey("id").SetNumeric(2); err == nil || err.Error() != "$['id'] is read-only" {
+		t.Errorf("SetNumeric() wrong error: %v", err)
+	}
+	if err = root.DeleteKey("id"); err == nil {
+		t.Errorf("DeleteKey() expected error")
+	}
+	if err = root.MustKey("data").MustKey("id").SetNumeric(4); err != nil {
+		t.Errorf("SetNumeric() unexpected error: %s", err)
+	}
+	if err = root.MustKey("data").DeleteKey("value"); err != nil {
+		t.Errorf("DeleteKey() unexpected error: %s", err)
+	}
+	if value := marshalString(root); value != `{"id":1,"data":{"id":4}}` {
+		t.Errorf("wrong result: %s", value)
+	}
+}
+
+func TestNode_Observe_subtree(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": 1}, "c": {"d": 2}}`)))
+	node := root.MustKey("a")
+	result := make([]string, 0)
+	cancel := node.Observe(func(event Event) {
+		result = append(result, eventString(event))
+	})
+	_ = root.MustKey("c").MustKey("d").SetNumeric(3)
+	_ = node.MustKey("b").SetNumeric(4)
+	// observers are kept by the node, when it's moved or replaced
+	_ = node.MoveTo(root.MustKey("c"), "a")
+	_ = node.SetNode(Must(Unmarshal([]byte(`{"e": 5}`))))
+	_ = node.MustKey("e").SetNumeric(6)
+	cancel()
+	_ = node.MustKey("e").SetNumeric(7)
+	expected := []string{
+		`set $['a']['b'] 1 4`,
+		`delete $['a'] {"b":4} -`,
+		`set $['c']['a'] {"b":4} {"e":5}`,
+		`set $['c']['a']['e'] 5 6`,
+	}
+	if !sliceEqual(result, expected) {
+		t.Errorf("wrong events:\n%s\nexpected:\n%s", sliceString(result), sliceString(expected))
+	}
+	cancel()
+	if len(node.observers) != 0 {
+		t.Errorf("observer is not removed")
+	}
+}
+
+func TestNode_Observe_transaction(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": 1, "b": [2]}`)))
+	tx, err := root.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	if err = root.MustKey("a").SetNumeric(3); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	result := make([]string, 0)
+	root.Observe(func(event Event) {
+		result = append(result, eventString(event))
+	})
+	if err = root.MustKey("b").AppendArray(NumericNode("", 4)); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	if err = tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %s", err)
+	}
+	expected := []string{
+		`append $['b'][1] - 4`,
+		`set $['b'] - [2]`,
+		`set $['a'] - 1`,
+	}
+	if !sliceEqual(result, expected) {
+		t.Errorf("wrong events:\n%s\nexpected:\n%s", sliceString(result), sliceString(expected))
+	}
+	if len(root.observers) != 1 {
+		t.Errorf("observer is lost after Rollback")
+	}
+}
+
+func ExampleNode_Observe() {
+	root := Must(Unmarshal([]byte(`{"users": [{"name": "alice"}]}`)))
+	cancel := root.Observe(func(event Event) {
+		fmt.Println(event.Type, event.Path, event.New)
+	})
+	defer cancel()
+	_ = root.MustKey("users").AppendArray(Must(Unmarshal([]byte(`{"name": "bob"}`))))
+	_ = root.MustKey("users").MustIndex(0).MustKey("name").SetString("carol")
+	// Output:
+	// append $['users'][1] {"name": "bob"}
+	// set $['users'][0]['name'] "carol"
+}
+
+func ExampleNode_Intercept() {
+	root := Must(Unmarshal([]byte(`{"id": 1, "name": "test"}`)))
+	root.Intercept(func(event Event) error {
+		if event.Type == EventDelete && event.Old.Key() == "id" {
+			return fmt.Errorf("%s is required", event.Path)
+		}
+		return nil
+	})
+	fmt.Println(root.DeleteKey("id"))
+	fmt.Println(root.DeleteKey("name"))
+	fmt.Println(root)
+	// Output:
+	// $['id'] is required
+	// <nil>
+	// {"id":1}
+}
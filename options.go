@@ -0,0 +1,101 @@
+package ajson
+
+// Options are the parsing options, used by UnmarshalWithOptions, and the marshaling options, used by
+// MarshalWithOptions.
+//
+// Zero value of Options means strict parsing, the same as Unmarshal does. Zero value of any limit means that there is
+// no such limit. If the limit is exceeded, parsing fails with the LimitExceeded error.
+type Options struct {
+	// Relaxed enables JSON5 syntax (https://json5.org/): comments, trailing commas, single-quoted strings, unquoted
+	// keys, additional escapes and whitespaces, hexadecimal numbers, numbers with leading plus or leading and trailing
+	// decimal point, Infinity and NaN.
+	//
+	// Nodes, which source is not a valid JSON, are marked as dirty, so Marshal will always produce a valid JSON. JSON
+	// has no representation for Infinity and NaN, so Marshal returns WrongRequest error for the documents with them.
+	Relaxed bool
+
+	// MaxDepth is the maximum nesting depth of arrays and objects, the root container has depth 1.
+	MaxDepth int
+	// MaxNodes is the maximum total count of nodes in the document.
+	MaxNodes int
+	// MaxStringLength is the maximum length of strings and keys, in bytes of the source.
+	MaxStringLength int
+	// MaxObjectKeys is the maximum count of members in a single object.
+	MaxObjectKeys int
+
+	// DuplicateKeys is the policy for the keys, that are repeated in the same object. Objects with the dropped values
+	// are marked as dirty, so Marshal writes only the values, that were kept.
+	DuplicateKeys DuplicateKeys
+
+	// SortKeys makes MarshalWithOptions write keys of all objects in the alphabetical order, instead of the order of
+	// Node.Keys. Objects are re-encoded in that case, even if they weren't changed.
+	SortKeys bool
+}
+
+// DiffOptions are the options of DiffWithOptions.
+type DiffOptions struct {
+	// Moves makes DiffWithOptions detect elements, moved inside the same array, and produce the "move" operations for
+	// them instead of "remove" and "add".
+	Moves bool
+}
+
+// MergeOptions are the options of MergeWithOptions.
+type MergeOptions struct {
+	// Arrays is the strategy for the arrays, that exist in both nodes.
+	Arrays MergeArrays
+	// Key is the name of the key field of objects in arrays, merged with MergeArraysByKey.
+	Key string
+	// Conflicts is the strategy for the values of different types.
+	Conflicts MergeConflicts
+}
+
+// SetPathOptions are the options of Node.SetPath.
+type SetPathOptions struct {
+	// CreatePath makes Node.SetPath create the missing objects and arrays of the path, if nothing was found by it.
+	CreatePath bool
+	// PadArrays is the value, which clones fill the missing elements of arrays before the index, set by Node.SetPath.
+	// Without it, the index after the end of the array is an error.
+	PadArrays *Node
+}
+
+// DuplicateKeys is the policy for the duplicated object keys on parsing
+type DuplicateKeys int
+
+const (
+	// DuplicateKeysLast keeps the last value of the duplicated key
+	DuplicateKeysLast DuplicateKeys = iota
+	// DuplicateKeysFirst keeps the first value of the duplicated key
+	DuplicateKeysFirst
+	// DuplicateKeysError fails parsing with the DuplicateKey error on the second occurrence of the key
+	DuplicateKeysError
+	// DuplicateKeysAll keeps the last value of the duplicated key as the child, and all values can be taken with
+	// Node.Duplicates
+	DuplicateKeysAll
+)
+
+// MergeArrays is the strategy of merging arrays
+type MergeArrays int
+
+const (
+	// MergeArraysReplace replaces the left array with the right one
+	MergeArraysReplace MergeArrays = iota
+	// MergeArraysConcat appends all elements of the right array to the left one
+	MergeArraysConcat
+	// MergeArraysUnion appends elements of the right array, that are not equal to any element of the left one
+	MergeArraysUnion
+	// MergeArraysByKey merges objects with the same value of the MergeOptions.Key field, other elements of the right
+	// array are appended to the left one
+	MergeArraysByKey
+)
+
+// MergeConflicts is the strategy of merging values of different types
+type MergeConflicts int
+
+const (
+	// MergeConflictsRight replaces the left value with the right one
+	MergeConflictsRight MergeConflicts = iota
+	// MergeConflictsLeft keeps the left value
+	MergeConflictsLeft
+	// MergeConflictsError fails merging with the WrongRequest error
+	MergeConflictsError
+)
@@ -0,0 +1,179 @@
+package ajson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalWithOptions_limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options Options
+		limit   string
+		index   int
+	}{
+		{name: "MaxDepth ok", input: `[[1], {"a": 2}]`, options: Options{MaxDepth: 2}},
+		{name: "MaxDepth", input: `[[1], {"a": [2]}]`, options: Options{MaxDepth: 2}, limit: "MaxDepth", index: 12},
+		{name: "MaxDepth root", input: `{}`, options: Options{MaxDepth: -1}},
+		{name: "MaxDepth scalar", input: `1`, options: Options{MaxDepth: 1}},
+		{name: "MaxNodes ok", input: `[1, 2, [3]]`, options: Options{MaxNodes: 5}},
+		{name: "MaxNodes", input: `[1, 2, [3, 4]]`, options: Options{MaxNodes: 5}, limit: "MaxNodes", index: 11},
+		{name: "MaxStringLength ok", input: `{"abc": "def"}`, options: Options{MaxStringLength: 3}},
+		{name: "MaxStringLength value", input: `{"abc": "defg"}`, options: Options{MaxStringLength: 3}, limit: "MaxStringLength", index: 8},
+		{name: "MaxStringLength key", input: `{"abcd": "def"}`, options: Options{MaxStringLength: 3}, limit: "MaxStringLength", index: 1},
+		{name: "MaxStringLength relaxed", input: `['abcd']`, options: Options{MaxStringLength: 3, Relaxed: true}, limit: "MaxStringLength", index: 1},
+		{name: "MaxObjectKeys ok", input: `{"a": 1, "b": {"c": 3, "d": 4}}`, options: Options{MaxObjectKeys: 2}},
+		{name: "MaxObjectKeys", input: `{"a": 1, "b": 2, "c": 3}`, options: Options{MaxObjectKeys: 2}, limit: "MaxObjectKeys", index: 22},
+		{name: "MaxObjectKeys arrays", input: `[1, 2, 3]`, options: Options{MaxObjectKeys: 2}},
+		{name: "MaxObjectKeys duplicates", input: `{"a": 1, "a": 2, "a": 3}`, options: Options{MaxObjectKeys: 2, DuplicateKeys: DuplicateKeysAll}, limit: "MaxObjectKeys", index: 22},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := UnmarshalWithOptions([]byte(test.input), test.options)
+			if test.limit == "" {
+				if err != nil {
+					t.Errorf("UnmarshalWithOptions() unexpected error: %s", err)
+				}
+				return
+			}
+			if root != nil {
+				t.Errorf("UnmarshalWithOptions() root is not nil")
+			}
+			current, ok := err.(Error)
+			if !ok {
+				t.Fatalf("UnmarshalWithOptions() wrong error: %v", err)
+			}
+			if current.Type != LimitExceeded || current.Message != test.limit || current.Index != test.index {
+				t.Errorf("UnmarshalWithOptions() wrong error: %s", err)
+			}
+		})
+	}
+}
+
+func TestNewDecoderWithOptions(t *testing.T) {
+	decoder := NewDecoderWithOptions(strings.NewReader(`[1] [[2]]`), Options{MaxDepth: 1})
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if _, err := decoder.Decode(); err == nil || err.(Error).Type != LimitExceeded {
+		t.Errorf("Decode() expected LimitExceeded error, got: %v", err)
+	}
+}
+
+func TestNewParserWithOptions(t *testing.T) {
+	parser := NewParserWithOptions(Options{MaxStringLength: 4})
+	if _, err := parser.Write([]byte(`["abc`)); err != nil {
+		t.Fatalf("Write() unexpected error: %s", err)
+	}
+	// string is incomplete yet, but it's already too long
+	if _, err := parser.Write([]byte(`de`)); err == nil || err.(Error).Type != LimitExceeded {
+		t.Errorf("Write() expected LimitExceeded error, got: %v", err)
+	}
+}
+
+func TestUnmarshalWithOptions_duplicates(t *testing.T) {
+	input := []byte(`{"a": 1, "b": {"c": 2}, "a": [3], "a": "4"}`)
+	tests := []struct {
+		name    string
+		policy  DuplicateKeys
+		value   string
+		values  []string
+		marshal string
+	}{
+		{name: "last", policy: DuplicateKeysLast, value: `"4"`, values: []string{`"4"`}, marshal: `{"a":"4","b":{"c": 2}}`},
+		{name: "first", policy: DuplicateKeysFirst, value: `1`, values: []string{`1`}, marshal: `{"a":1,"b":{"c": 2}}`},
+		{name: "all", policy: DuplicateKeysAll, value: `"4"`, values: []string{`1`, `[3]`, `"4"`}, marshal: string(input)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := UnmarshalWithOptions(input, Options{DuplicateKeys: test.policy})
+			if err != nil {
+				t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+			}
+			if root.Size() != 2 {
+				t.Errorf("wrong size: %d", root.Size())
+			}
+			if value := string(root.MustKey("a").Source()); value != test.value {
+				t.Errorf("wrong value: %s, expected: %s", value, test.value)
+			}
+			values := make([]string, 0)
+			for _, node := range root.Duplicates("a") {
+				values = append(values, string(node.Source()))
+			}
+			if !sliceEqual(values, test.values) {
+				t.Errorf("Duplicates() wrong result: %s, expected: %s", sliceString(values), sliceString(test.values))
+			}
+			if root.Duplicates("b")[0] != root.MustKey("b") || root.Duplicates("c") != nil {
+				t.Errorf("Duplicates() wrong result for unique keys")
+			}
+			if result, err := Marshal(root); err != nil || string(result) != test.marshal {
+				t.Errorf("Marshal() wrong result: %s, expected: %s", result, test.marshal)
+			}
+		})
+	}
+	root, err := UnmarshalWithOptions([]byte(`[{"a": 1, "b": [1], "a": 2}, {"c": 3}]`), Options{DuplicateKeys: DuplicateKeysFirst})
+	if err != nil {
+		t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+	if result, err := Marshal(root); err != nil || string(result) != `[{"a":1,"b":[1]},{"c": 3}]` {
+		t.Errorf("Marshal() wrong result: %s", result)
+	}
+}
+
+func TestUnmarshalWithOptions_duplicates_detached(t *testing.T) {
+	root, err := UnmarshalWithOptions([]byte(`{"a": 1, "a": 2}`), Options{DuplicateKeys: DuplicateKeysAll})
+	if err != nil {
+		t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+	previous := root.Duplicates("a")[0]
+	if previous.Parent() != nil {
+		t.Errorf("Parent() of the replaced value is not nil")
+	}
+	if err = previous.Delete(); err != nil {
+		t.Fatalf("Delete() unexpected error: %s", err)
+	}
+	if value, err := root.GetKey("a"); err != nil || value.MustNumeric() != 2 {
+		t.Errorf("Delete() of the replaced value changed the object: %v", root)
+	}
+}
+
+func TestUnmarshalWithOptions_duplicates_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options Options
+		index   int
+	}{
+		{name: "strict", input: `{"a": 1, "b": 2, "a": 3}`, options: Options{DuplicateKeys: DuplicateKeysError}, index: 17},
+		{name: "nested", input: `[{"a": {"b": 1, "b": 2}}]`, options: Options{DuplicateKeys: DuplicateKeysError}, index: 16},
+		{name: "relaxed", input: `{a: 1, 'a': 2}`, options: Options{DuplicateKeys: DuplicateKeysError, Relaxed: true}, index: 7},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := UnmarshalWithOptions([]byte(test.input), test.options)
+			if current, ok := err.(Error); !ok || current.Type != DuplicateKey || current.Index != test.index {
+				t.Errorf("UnmarshalWithOptions() wrong error: %v", err)
+			}
+		})
+	}
+	if _, err := UnmarshalWithOptions([]byte(`[{"a": 1}, {"a": 2}]`), Options{DuplicateKeys: DuplicateKeysError}); err != nil {
+		t.Errorf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+}
+
+func TestNode_Duplicates_mutations(t *testing.T) {
+	root, err := UnmarshalWithOptions([]byte(`{"a": 1, "a": 2}`), Options{DuplicateKeys: DuplicateKeysAll})
+	if err != nil {
+		t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+	if err = root.DeleteKey("a"); err != nil {
+		t.Fatalf("DeleteKey() unexpected error: %s", err)
+	}
+	if err = root.AppendObject("a", NumericNode("", 3)); err != nil {
+		t.Fatalf("AppendObject() unexpected error: %s", err)
+	}
+	if values := root.Duplicates("a"); len(values) != 1 || values[0].MustNumeric() != 3 {
+		t.Errorf("Duplicates() wrong result after mutations")
+	}
+}
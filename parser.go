@@ -0,0 +1,78 @@
+package ajson
+
+import (
+	"io"
+)
+
+// Parser is an incremental JSON parser: data can be written into it by chunks of any size, parsing continues from the
+// place where the previous chunk ended.
+//
+// Parser implements io.WriteCloser, so it can be used as a destination of io.Copy.
+type Parser struct {
+	parser *parser
+	root   *Node
+	err    error
+}
+
+// NewParser returns a new incremental parser for a single JSON document.
+func NewParser() *Parser {
+	return NewParserWithOptions(Options{})
+}
+
+// NewParserWithOptions returns a new incremental parser for a single JSON document with the given options.
+func NewParserWithOptions(options Options) *Parser {
+	p := newParser(nil)
+	p.options = options
+	p.stream = true
+	return &Parser{
+		parser: p,
+	}
+}
+
+// Write appends the chunk of data to the document and continues parsing. Chunk is copied, so it can be reused after
+// the call.
+//
+// Write doesn't return an error, if the document is incomplete yet. Once the document is complete, it can be taken
+// with Parser.Root, and only whitespaces are allowed to be written after it.
+func (p *Parser) Write(chunk []byte) (n int, err error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	buf := p.parser.buf
+	buf.data = append(buf.data, chunk...)
+	buf.length = len(buf.data)
+	if p.root == nil {
+		p.root, p.err = p.parser.parse(false)
+	}
+	if p.err == nil && p.root != nil {
+		if err = p.parser.first(false); err == nil {
+			p.err = buf.errorSymbol()
+		} else if err != io.EOF {
+			p.err = err
+		}
+	}
+	if p.err != nil {
+		p.err = locate(p.err, buf.data)
+		return 0, p.err
+	}
+	return len(chunk), nil
+}
+
+// Close finishes parsing. It returns UnexpectedEOF error, if the document is incomplete.
+func (p *Parser) Close() error {
+	if p.err == nil && p.root == nil {
+		p.root, p.err = p.parser.parse(true)
+		if p.err != nil {
+			p.err = locate(p.err, p.parser.buf.data)
+		}
+	}
+	return p.err
+}
+
+// Root returns the root node of the document, if it's complete, or nil otherwise.
+func (p *Parser) Root() *Node {
+	if p.err != nil {
+		return nil
+	}
+	return p.root
+}
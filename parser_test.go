@@ -0,0 +1,137 @@
+package ajson
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParser_Write(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		// index of the chunk, after which the root is ready
+		ready int
+		want  string
+	}{
+		{name: "single", chunks: []string{`{"foo":"bar"}`}, ready: 0, want: `{"foo":"bar"}`},
+		{name: "split string", chunks: []string{`["fo`, `o\`, `"`, `"]`}, ready: 3, want: `["foo\""]`},
+		{name: "split escapes", chunks: []string{`["a\`, `\`, `", "\`, `""]`}, ready: 3, want: `["a\\", "\""]`},
+		{name: "split key", chunks: []string{`{"f`, `oo":`, `1}`}, ready: 2, want: `{"foo":1}`},
+		{name: "split numeric", chunks: []string{`[12`, `3.4`, `5e1`, `0]`}, ready: 3, want: `[123.45e10]`},
+		{name: "split literal", chunks: []string{`[tr`, `ue,fal`, `se,n`, `ull]`}, ready: 3, want: `[true,false,null]`},
+		{name: "trailing spaces", chunks: []string{`[]`, ` `, "\n"}, ready: 0, want: `[]`},
+		{name: "empty chunks", chunks: []string{``, `[`, ``, `]`, ``}, ready: 3, want: `[]`},
+		{name: "numeric", chunks: []string{`1`, `2`, `3`}, ready: -1, want: `123`},
+		{name: "literal", chunks: []string{`tr`, `ue`}, ready: -1, want: `true`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := NewParser()
+			for i, chunk := range test.chunks {
+				n, err := parser.Write([]byte(chunk))
+				if err != nil {
+					t.Fatalf("Write() unexpected error: %s", err)
+				}
+				if n != len(chunk) {
+					t.Errorf("Write() wrong size: %d, expected %d", n, len(chunk))
+				}
+				if ready := parser.Root() != nil; ready != (test.ready != -1 && i >= test.ready) {
+					t.Errorf("Root() wrong state after chunk #%d", i)
+				}
+			}
+			if err := parser.Close(); err != nil {
+				t.Fatalf("Close() unexpected error: %s", err)
+			}
+			if result := string(parser.Root().Source()); result != test.want {
+				t.Errorf("Root() wrong result: %s, expected: %s", result, test.want)
+			}
+		})
+	}
+}
+
+func TestParser_Write_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+	}{
+		{name: "wrong symbol", chunks: []string{`[1,`, `]`}},
+		{name: "trailing symbol", chunks: []string{`[1]`, ` 2`}},
+		{name: "wrong key", chunks: []string{`{`, `1:1}`}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := NewParser()
+			var err error
+			for _, chunk := range test.chunks {
+				if _, err = parser.Write([]byte(chunk)); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				t.Fatalf("Write() expected error")
+			}
+			if current, ok := err.(Error); !ok || current.Type != WrongSymbol {
+				t.Errorf("Write() wrong error: %s", err)
+			}
+			if _, next := parser.Write([]byte(` `)); next != err {
+				t.Errorf("Write() error was not saved: %v", next)
+			}
+			if parser.Root() != nil {
+				t.Errorf("Root() expected to be nil")
+			}
+		})
+	}
+}
+
+func TestParser_Close(t *testing.T) {
+	for _, input := range []string{``, ` `, `[`, `{"foo"`, `"foo`, `[1,2`, `1e`} {
+		t.Run(input, func(t *testing.T) {
+			parser := NewParser()
+			if _, err := parser.Write([]byte(input)); err != nil {
+				t.Fatalf("Write() unexpected error: %s", err)
+			}
+			err := parser.Close()
+			if err == nil {
+				t.Fatalf("Close() expected error")
+			}
+			if input == `1e` {
+				return
+			}
+			if current, ok := err.(Error); !ok || current.Type != UnexpectedEOF {
+				t.Errorf("Close() wrong error: %s", err)
+			}
+		})
+	}
+}
+
+func ExampleParser() {
+	parser := NewParser()
+	for _, chunk := range []string{`{"id": 1, "na`, `me": "fo`, `o"}`} {
+		if _, err := parser.Write([]byte(chunk)); err != nil {
+			panic(err)
+		}
+		if root := parser.Root(); root != nil {
+			fmt.Printf("%s: %s\n", root.MustKey("id").Source(), root.MustKey("name").MustString())
+		}
+	}
+	if err := parser.Close(); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 1: foo
+}
+
+func ExampleParser_copy() {
+	parser := NewParser()
+	if _, err := io.Copy(parser, strings.NewReader(`[1, 2, 3]`)); err != nil {
+		panic(err)
+	}
+	if err := parser.Close(); err != nil {
+		panic(err)
+	}
+	fmt.Println(parser.Root().Size())
+	// Output:
+	// 3
+}
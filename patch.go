@@ -0,0 +1,152 @@
+package ajson
+
+import (
+	"strings"
+)
+
+// This file contains the support of JSON Patch (RFC 6902): https://tools.ietf.org/html/rfc6902
+
+// ApplyPatch applies JSON Patch to the document. Patch is the array of operations: add, remove, replace, move, copy
+// and test; pointers of the operations are relative to the document.
+//
+// Operations are checked on the clone of the document first, and then applied to the document itself, only if all of
+// them succeeded, so it stays unchanged on error. Only the nodes, targeted by the operations, are changed, other
+// nodes of the document are kept with their observers. Error of the failed operation is WrongPatch, with its index.
+func ApplyPatch(doc, patch *Node) error {
+	if doc == nil || patch == nil {
+		return errorUnparsed()
+	}
+	if !patch.IsArray() {
+		return errorRequest("patch must be an array of operations")
+	}
+	operations := patch.Inheritors()
+	if err := applyOperations(doc.Clone(), operations); err != nil {
+		return err
+	}
+	return doc.atomically(func() error {
+		return applyOperations(doc, operations)
+	})
+}
+
+// applyOperations applies the operations of JSON Patch to the document one by one
+func applyOperations(doc *Node, operations []*Node) error {
+	for i, operation := range operations {
+		if err := applyOperation(doc, operation); err != nil {
+			return errorPatch(i, err)
+		}
+	}
+	return nil
+}
+
+// applyOperation applies the single operation of JSON Patch to the document
+func applyOperation(doc, operation *Node) error {
+	if !operation.IsObject() {
+		return errorRequest("operation must be an object")
+	}
+	op, err := operationMember(operation, "op")
+	if err != nil {
+		return err
+	}
+	path, err := operationMember(operation, "path")
+	if err != nil {
+		return err
+	}
+	switch op {
+	case "add", "replace", "test":
+		value, ok := operation.children["value"]
+		if !ok {
+			return errorRequest("member 'value' is required for '%s'", op)
+		}
+		if op == "add" {
+			return patchAdd(doc, path, value.Clone())
+		}
+		target, err := doc.GetPointer(path)
+		if err != nil {
+			return err
+		}
+		if op == "replace" {
+			return target.SetNode(value)
+		}
+		equal, err := target.Eq(value)
+		if err != nil {
+			return err
+		}
+		if !equal {
+			return errorRequest("test failed: value of '%s' is not equal to %s", path, value)
+		}
+		return nil
+	case "remove":
+		return doc.DeletePointer(path)
+	case "move", "copy":
+		from, err := operationMember(operation, "from")
+		if err != nil {
+			return err
+		}
+		node, err := doc.GetPointer(from)
+		if err != nil {
+			return err
+		}
+		if op == "copy" {
+			return patchAdd(doc, path, node.Clone())
+		}
+		if from == path {
+			return nil
+		}
+		if strings.HasPrefix(path, from+"/") {
+			return errorRequest("'%s' can't be moved into its own child '%s'", from, path)
+		}
+		if err = node.parent.remove(node); err != nil {
+			return err
+		}
+		return patchAdd(doc, path, node)
+	}
+	return errorRequest("unknown operation '%s'", op)
+}
+
+// operationMember returns the string member of the operation
+func operationMember(operation *Node, key string) (string, error) {
+	member, ok := operation.children[key]
+	if !ok {
+		return "", errorRequest("member '%s' is required", key)
+	}
+	value, err := member.GetString()
+	if err != nil {
+		return "", errorRequest("member '%s' must be a string", key)
+	}
+	return value, nil
+}
+
+// patchAdd adds the value by pointer: replaces the root or the value of the existing key, or inserts the value into
+// the array
+func patchAdd(doc *Node, pointer string, value *Node) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return doc.SetNode(value)
+	}
+	parent, err := doc.resolvePointer(pointer, tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+	token := tokens[len(tokens)-1]
+	switch parent.Type() {
+	case Object:
+		return parent.AppendObject(token, value)
+	case Array:
+		if token == "-" {
+			return parent.AppendArray(value)
+		}
+		index, err := pointerIndex(pointer, token, len(parent.children))
+		if err != nil {
+			return err
+		}
+		if err = parent.insertNode(index, value); err != nil {
+			return err
+		}
+		parent.mark()
+		return nil
+	}
+	return errorRequest("wrong pointer '%s': parent is not a container", pointer)
+}
@@ -0,0 +1,152 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	// examples from RFC 6902, Appendix A
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{name: "add object member", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`, expected: `{"foo":"bar","baz":"qux"}`},
+		{name: "add array element", doc: `{"foo": ["bar", "baz"]}`, patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, expected: `{"foo":["bar","qux","baz"]}`},
+		{name: "add to the end", doc: `{"foo": ["bar"]}`, patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`, expected: `{"foo":["bar",["abc","def"]]}`},
+		{name: "add existing member", doc: `{"foo": 1, "bar": 2}`, patch: `[{"op": "add", "path": "/foo", "value": null}]`, expected: `{"foo":null,"bar":2}`},
+		{name: "add nested member", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`, expected: `{"foo":"bar","child":{"grandchild":{}}}`},
+		{name: "add root", doc: `{"foo": "bar"}`, patch: `[{"op": "add", "path": "", "value": [1]}]`, expected: `[1]`},
+		{name: "remove object member", doc: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "remove", "path": "/baz"}]`, expected: `{"foo":"bar"}`},
+		{name: "remove array element", doc: `{"foo": ["bar", "qux", "baz"]}`, patch: `[{"op": "remove", "path": "/foo/1"}]`, expected: `{"foo":["bar","baz"]}`},
+		{name: "replace", doc: `{"baz": "qux", "foo": "bar"}`, patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`, expected: `{"baz":"boo","foo":"bar"}`},
+		{name: "move value", doc: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`, expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{name: "move array element", doc: `{"foo": ["all", "grass", "cows", "eat"]}`, patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, expected: `{"foo":["all","cows","eat","grass"]}`},
+		{name: "move to itself", doc: `{"foo": [1]}`, patch: `[{"op": "move", "from": "/foo", "path": "/foo"}]`, expected: `{"foo":[1]}`},
+		{name: "copy", doc: `{"foo": {"bar": [1]}}`, patch: `[{"op": "copy", "from": "/foo/bar", "path": "/baz"}, {"op": "add", "path": "/baz/-", "value": 2}]`, expected: `{"foo":{"bar":[1]},"baz":[1,2]}`},
+		{name: "test", doc: `{"baz": "qux", "foo": ["a", 2, "c"]}`, patch: `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`, expected: `{"baz":"qux","foo":["a",2,"c"]}`},
+		{name: "escape", doc: `{"/": 9, "~1": 10}`, patch: `[{"op": "test", "path": "/~01", "value": 10}, {"op": "remove", "path": "/~1"}]`, expected: `{"~1":10}`},
+		{name: "empty", doc: `{"foo": 1}`, patch: `[]`, expected: `{"foo":1}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := Must(Unmarshal([]byte(test.doc)))
+			patch := Must(Unmarshal([]byte(test.patch)))
+			if err := ApplyPatch(doc, patch); err != nil {
+				t.Fatalf("ApplyPatch() unexpected error: %s", err)
+			}
+			if equal, err := doc.Eq(Must(Unmarshal([]byte(test.expected)))); err != nil || !equal {
+				t.Errorf("ApplyPatch() wrong result: %s, expected: %s", doc, test.expected)
+			}
+			if patch.String() != test.patch {
+				t.Errorf("ApplyPatch() changed the patch: %s", patch)
+			}
+		})
+	}
+}
+
+func TestApplyPatch_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		index int
+	}{
+		{name: "test failed", patch: `[{"op": "add", "path": "/baz", "value": 1}, {"op": "test", "path": "/foo", "value": "baz"}]`, index: 1},
+		{name: "missing target", patch: `[{"op": "remove", "path": "/foo"}, {"op": "remove", "path": "/foo"}]`, index: 1},
+		{name: "missing parent", patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`, index: 0},
+		{name: "index out of range", patch: `[{"op": "add", "path": "/arr/3", "value": 1}]`, index: 0},
+		{name: "replace missing", patch: `[{"op": "replace", "path": "/baz", "value": 1}]`, index: 0},
+		{name: "move into child", patch: `[{"op": "move", "from": "/arr", "path": "/arr/0"}]`, index: 0},
+		{name: "remove root", patch: `[{"op": "remove", "path": ""}]`, index: 0},
+		{name: "unknown op", patch: `[{"op": "test", "path": "/foo", "value": "bar"}, {"op": "merge", "path": "/foo"}]`, index: 1},
+		{name: "missing value", patch: `[{"op": "add", "path": "/baz"}]`, index: 0},
+		{name: "missing from", patch: `[{"op": "copy", "path": "/baz"}]`, index: 0},
+		{name: "wrong op", patch: `[{"op": 1, "path": "/baz"}]`, index: 0},
+		{name: "wrong operation", patch: `[[]]`, index: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			doc := Must(Unmarshal([]byte(`{"foo": "bar", "arr": [1, 2]}`)))
+			foo := doc.MustKey("foo")
+			err := ApplyPatch(doc, Must(Unmarshal([]byte(test.patch))))
+			if err == nil {
+				t.Fatalf("ApplyPatch() expected error")
+			}
+			if current, ok := err.(Error); !ok || current.Type != WrongPatch || current.Index != test.index {
+				t.Errorf("ApplyPatch() wrong error: %#v", err)
+			}
+			if doc.String() != `{"foo": "bar", "arr": [1, 2]}` || doc.IsDirty() || doc.MustKey("foo") != foo {
+				t.Errorf("ApplyPatch() changed the document: %s", doc)
+			}
+		})
+	}
+
+	if err := ApplyPatch(Must(Unmarshal([]byte(`{}`))), Must(Unmarshal([]byte(`{}`)))); err == nil {
+		t.Errorf("ApplyPatch() expected error for the object patch")
+	}
+	if err := ApplyPatch(nil, Must(Unmarshal([]byte(`[]`)))); err == nil {
+		t.Errorf("ApplyPatch() expected error for nil document")
+	}
+}
+
+func TestApplyPatch_inPlace(t *testing.T) {
+	doc := Must(Unmarshal([]byte(`{"a": {"x": 1}, "b": 2}`)))
+	a := doc.MustKey("a")
+	events := 0
+	a.Observe(func(event Event) {
+		events++
+	})
+	if err := ApplyPatch(doc, Must(Unmarshal([]byte(`[{"op": "replace", "path": "/b", "value": 3}]`)))); err != nil {
+		t.Fatalf("ApplyPatch() unexpected error: %s", err)
+	}
+	if doc.MustKey("a") != a || a.IsDirty() {
+		t.Errorf("ApplyPatch() changed the untouched node")
+	}
+	if err := a.MustKey("x").SetNumeric(5); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	if result := doc.String(); result != `{"a":{"x":5},"b":3}` || events != 1 {
+		t.Errorf("ApplyPatch() wrong result: %s, events: %d", result, events)
+	}
+}
+
+func TestApplyPatch_intercepted(t *testing.T) {
+	doc := Must(Unmarshal([]byte(`{"a": 1, "b": 2}`)))
+	doc.MustKey("b").Intercept(func(event Event) error {
+		return fmt.Errorf("read-only")
+	})
+	patch := Must(Unmarshal([]byte(`[{"op": "remove", "path": "/a"}, {"op": "replace", "path": "/b", "value": 3}]`)))
+	if err := ApplyPatch(doc, patch); err == nil {
+		t.Fatalf("ApplyPatch() expected error")
+	}
+	if result := doc.String(); result != `{"a": 1, "b": 2}` || doc.transaction != nil {
+		t.Errorf("ApplyPatch() changed the document: %s", result)
+	}
+	tx, err := doc.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	if err = ApplyPatch(doc, patch); err == nil {
+		t.Fatalf("ApplyPatch() expected error")
+	}
+	if result := doc.String(); result != `{"a": 1, "b": 2}` || tx.CanUndo() {
+		t.Errorf("ApplyPatch() changed the document: %s", result)
+	}
+}
+
+func ExampleApplyPatch() {
+	doc := Must(Unmarshal([]byte(`{"name": "ajson", "tags": ["json"]}`)))
+	patch := Must(Unmarshal([]byte(`[
+		{"op": "replace", "path": "/name", "value": "AJSON"},
+		{"op": "add", "path": "/tags/0", "value": "go"},
+		{"op": "test", "path": "/tags/1", "value": "yaml"}
+	]`)))
+	err := ApplyPatch(doc, patch)
+	fmt.Println(err)
+	fmt.Println(doc)
+	// Output:
+	// patch operation 2 failed: wrong request: test failed: value of '/tags/1' is not equal to "yaml"
+	// {"name": "ajson", "tags": ["json"]}
+}
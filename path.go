@@ -0,0 +1,251 @@
+package ajson
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SetPath sets the clone of the value to every node, found by the JSONPath, relative to the current node.
+//
+// If nothing was found and the path is the simple chain of keys and indexes, e.g. "$.metadata.labels.team" or
+// "@.items[0].name", SetPathOptions.CreatePath makes it create the missing nodes: objects for keys and arrays for indexes.
+// Missing elements of arrays before the index are filled with the clones of SetPathOptions.PadArrays. Nothing is changed, if
+// the path can't be created.
+//
+// Example:
+//
+// 	err := root.SetPath("$.metadata.labels.team", StringNode("", "core"), SetPathOptions{CreatePath: true})
+func (n *Node) SetPath(path string, value *Node, options SetPathOptions) error {
+	if n == nil {
+		return errorUnparsed()
+	}
+	if value == nil {
+		return errorRequest("value is nil")
+	}
+	commands, err := ParseJSONPath(path)
+	if err != nil {
+		return err
+	}
+	nodes, err := ApplyJSONPath(n, commands)
+	if err != nil {
+		return err
+	}
+	if len(nodes) > 0 {
+		for _, node := range nodes {
+			if err = node.SetNode(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !options.CreatePath {
+		return nil
+	}
+	steps, ok := pathChain(commands)
+	if !ok {
+		return errorRequest("path '%s' can't be created", path)
+	}
+	node := n
+	if commands[0] == "$" {
+		node = n.root()
+	}
+	for len(steps) > 0 {
+		child := node.pathChild(steps[0])
+		if child == nil {
+			break
+		}
+		node, steps = child, steps[1:]
+	}
+	if len(steps) == 0 {
+		return node.SetNode(value)
+	}
+	// missing nodes are created first, so nothing is changed on error
+	child := value.Clone()
+	for i := len(steps) - 1; i > 0; i-- {
+		container := ObjectNode("", nil)
+		if steps[i].array {
+			container = ArrayNode("", nil)
+		}
+		if err = container.pathAppend(steps[i], child, options); err != nil {
+			return err
+		}
+		child = container
+	}
+	return node.pathAppend(steps[0], child, options)
+}
+
+// DeletePath removes all nodes, found by the JSONPath, relative to the root, and returns the count of removed nodes.
+//
+// Each node is removed once, nodes inside the other removed nodes are skipped. Nodes are removed by reference, so the
+// shift of array indexes doesn't change the result. Root of the document can't be removed, nothing is changed in that
+// case.
+func DeletePath(root *Node, path string) (int, error) {
+	nodes, err := pathMatches(root, path)
+	if err != nil {
+		return 0, err
+	}
+	matched := make(map[*Node]bool, len(nodes))
+	for _, node := range nodes {
+		if node.parent == nil {
+			return 0, errorRequest("root of the document can't be deleted")
+		}
+		matched[node] = true
+	}
+	count := 0
+	for _, node := range nodes {
+		if node.isParentIn(matched) {
+			continue
+		}
+		if err = node.parent.remove(node); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// UpdatePath calls the function for all nodes, found by the JSONPath, relative to the root, and returns the count of
+// updated nodes. Result of the function replaces the node (see Node.SetNode), nil result removes it, the node itself
+// keeps the changes made by the function.
+//
+// Each node is updated once, deeper nodes first, so the function gets the container with the already updated children.
+// Nodes, removed from the document by the previous calls, are skipped.
+func UpdatePath(root *Node, path string, fn func(node *Node) (*Node, error)) (int, error) {
+	nodes, err := pathMatches(root, path)
+	if err != nil {
+		return 0, err
+	}
+	top := root.root()
+	count := 0
+	for _, node := range nodes {
+		if node.root() != top {
+			continue
+		}
+		result, err := fn(node)
+		if err != nil {
+			return count, err
+		}
+		switch {
+		case result == nil && node.parent == nil:
+			return count, errorRequest("root of the document can't be deleted")
+		case result == nil:
+			err = node.parent.remove(node)
+		case result != node:
+			err = node.SetNode(result)
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// pathMatches returns the unique nodes, found by the JSONPath, ordered from the deepest ones
+func pathMatches(root *Node, path string) ([]*Node, error) {
+	if root == nil {
+		return nil, errorUnparsed()
+	}
+	found, err := root.JSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*Node, 0, len(found))
+	depths := make(map[*Node]int, len(found))
+	for _, node := range found {
+		if _, ok := depths[node]; ok {
+			continue
+		}
+		depth := 0
+		for current := node.parent; current != nil; current = current.parent {
+			depth++
+		}
+		depths[node] = depth
+		nodes = append(nodes, node)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return depths[nodes[i]] > depths[nodes[j]]
+	})
+	return nodes, nil
+}
+
+// isParentIn check if one of the parents of the current node is in the set
+func (n *Node) isParentIn(set map[*Node]bool) bool {
+	for current := n.parent; current != nil; current = current.parent {
+		if set[current] {
+			return true
+		}
+	}
+	return false
+}
+
+// pathStep is the step of the simple chain of the path: the key of the object or the index of the array
+type pathStep struct {
+	key   string
+	index int
+	array bool
+}
+
+// pathChain returns the steps of the path, if it is the simple chain of keys and indexes
+func pathChain(commands []string) ([]pathStep, bool) {
+	if len(commands) == 0 || (commands[0] != "$" && commands[0] != "@") {
+		return nil, false
+	}
+	steps := make([]pathStep, 0, len(commands)-1)
+	for _, cmd := range commands[1:] {
+		if cmd == "*" || cmd == ".." || strings.HasPrefix(cmd, "(") || strings.HasPrefix(cmd, "?(") {
+			return nil, false
+		}
+		tokens, err := newBuffer([]byte(cmd)).tokenize()
+		if err != nil || tokens.exists(":") || tokens.exists(",") {
+			return nil, false
+		}
+		key, ok := str(cmd)
+		if !ok {
+			return nil, false
+		}
+		step := pathStep{key: key}
+		if key == cmd {
+			if index, err := strconv.Atoi(cmd); err == nil {
+				step.index, step.array = index, true
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, true
+}
+
+// pathChild returns the existing child of the node by the step of the path, or nil
+func (n *Node) pathChild(step pathStep) *Node {
+	switch {
+	case n.IsObject():
+		return n.children[step.key]
+	case n.IsArray() && step.array:
+		return n.children[strconv.Itoa(getPositiveIndex(step.index, len(n.children)))]
+	}
+	return nil
+}
+
+// pathAppend appends the child to the node by the step of the path, array is padded up to the index
+func (n *Node) pathAppend(step pathStep, child *Node, options SetPathOptions) error {
+	switch {
+	case n.IsObject():
+		return n.AppendObject(step.key, child)
+	case n.IsArray() && step.array:
+		if step.index < len(n.children) {
+			return errorRequest("index %d of %s can't be created", step.index, n.Path())
+		}
+		if step.index > len(n.children) && options.PadArrays == nil {
+			return errorRequest("index %d of %s is after the end of the array", step.index, n.Path())
+		}
+		nodes := make([]*Node, 0, step.index-len(n.children)+1)
+		for i := len(n.children); i < step.index; i++ {
+			nodes = append(nodes, options.PadArrays.Clone())
+		}
+		return n.AppendArray(append(nodes, child)...)
+	case n.IsArray():
+		return errorRequest("key '%s' can't be created in the array %s", step.key, n.Path())
+	}
+	return errorRequest("%s is not a container", n.Path())
+}
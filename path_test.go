@@ -0,0 +1,205 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNode_SetPath(t *testing.T) {
+	create := SetPathOptions{CreatePath: true}
+	pad := SetPathOptions{CreatePath: true, PadArrays: NullNode("")}
+	tests := []struct {
+		name     string
+		path     string
+		options  SetPathOptions
+		expected string
+		err      bool
+	}{
+		{name: "existing", path: "$.a.b", expected: `{"a":{"b":"X"},"c":[1, {"d": 2}]}`},
+		{name: "all matches", path: "$..d", expected: `{"a":{"b": 1},"c":[1,{"d":"X"}]}`},
+		{name: "wildcard", path: "$.c[*]", expected: `{"a":{"b": 1},"c":["X","X"]}`},
+		{name: "root", path: "$", expected: `"X"`},
+		{name: "missing without create", path: "$.x.y", expected: `{"a": {"b": 1}, "c": [1, {"d": 2}]}`},
+		{name: "missing key", path: "$.a.x", options: create, expected: `{"a":{"b": 1,"x":"X"},"c":[1, {"d": 2}]}`},
+		{name: "missing chain", path: "$.x['y z'][0].w", options: create, expected: `{"a": {"b": 1},"c": [1, {"d": 2}],"x":{"y z":[{"w":"X"}]}}`},
+		{name: "append index", path: "$.c[2]", options: create, expected: `{"a": {"b": 1},"c":[1, {"d": 2},"X"]}`},
+		{name: "append into element", path: "$.c[1].e", options: create, expected: `{"a": {"b": 1},"c":[1,{"d": 2,"e":"X"}]}`},
+		{name: "padded index", path: "$.c[4]", options: pad, expected: `{"a": {"b": 1},"c":[1, {"d": 2},null,null,"X"]}`},
+		{name: "padded new array", path: "$.x[2]", options: pad, expected: `{"a": {"b": 1}, "c": [1, {"d": 2}],"x":[null,null,"X"]}`},
+		{name: "numeric key of object", path: "$.a.0", options: create, expected: `{"a":{"b": 1,"0":"X"},"c":[1, {"d": 2}]}`},
+		{name: "index without padding", path: "$.c[4]", options: create, err: true},
+		{name: "new array without padding", path: "$.x.y[1]", options: create, err: true},
+		{name: "negative index", path: "$.c[-3]", options: create, err: true},
+		{name: "key of array", path: "$.c.x", options: create, err: true},
+		{name: "key of scalar", path: "$.a.b.c", options: create, err: true},
+		{name: "not a chain", path: "$.x[*].y", options: create, err: true},
+		{name: "wrong path", path: "$.a[", options: create, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": {"b": 1}, "c": [1, {"d": 2}]}`)))
+			err := root.SetPath(test.path, StringNode("", "X"), test.options)
+			if test.err {
+				if err == nil {
+					t.Errorf("SetPath() expected error")
+				}
+				if root.String() != `{"a": {"b": 1}, "c": [1, {"d": 2}]}` {
+					t.Errorf("SetPath() changed the node on error: %s", root)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetPath() unexpected error: %s", err)
+			}
+			if !equal(root, Must(Unmarshal([]byte(test.expected)))) {
+				t.Errorf("SetPath() wrong result: %s, expected: %s", root, test.expected)
+			}
+		})
+	}
+}
+
+func TestNode_SetPath_relative(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": {}}}`)))
+	node := root.MustKey("a").MustKey("b")
+	if err := node.SetPath("@.c.d", NumericNode("", 1), SetPathOptions{CreatePath: true}); err != nil {
+		t.Fatalf("SetPath() unexpected error: %s", err)
+	}
+	if err := node.SetPath("$.e", NumericNode("", 2), SetPathOptions{CreatePath: true}); err != nil {
+		t.Fatalf("SetPath() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"a":{"b":{"c":{"d":1}}},"e":2}` {
+		t.Errorf("SetPath() wrong result: %s", result)
+	}
+	if err := (*Node)(nil).SetPath("$", NullNode(""), SetPathOptions{}); err == nil {
+		t.Errorf("SetPath() expected error for nil node")
+	}
+	if err := root.SetPath("$", nil, SetPathOptions{}); err == nil {
+		t.Errorf("SetPath() expected error for nil value")
+	}
+}
+
+func ExampleNode_SetPath() {
+	root := Must(Unmarshal([]byte(`{"metadata": {"name": "app"}}`)))
+	err := root.SetPath("$.metadata.labels.team", StringNode("", "core"), SetPathOptions{CreatePath: true})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(root)
+	// Output:
+	// {"metadata":{"name":"app","labels":{"team":"core"}}}
+}
+
+func TestDeletePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+		count    int
+		err      bool
+	}{
+		{name: "array elements", path: "$.a[?(@ > 1)]", expected: `{"a":[1],"b":{"c":[2,{"d":3}],"d":4}}`, count: 3},
+		{name: "all elements", path: "$.a[*]", expected: `{"a":[],"b":{"c":[2,{"d":3}],"d":4}}`, count: 4},
+		{name: "union duplicates", path: "$.a[0,0,-1]", expected: `{"a":[2,3],"b":{"c":[2,{"d":3}],"d":4}}`, count: 2},
+		{name: "nested matches", path: "$..d", expected: `{"a":[1,2,3,4],"b":{"c":[2,{}]}}`, count: 2},
+		{name: "parent and child", path: "$..*", expected: `{}`, count: 2},
+		{name: "nothing", path: "$.x", expected: `{"a":[1,2,3,4],"b":{"c":[2,{"d":3}],"d":4}}`, count: 0},
+		{name: "root", path: "$", err: true},
+		{name: "wrong path", path: "$.a[", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": [1, 2, 3, 4], "b": {"c": [2, {"d": 3}], "d": 4}}`)))
+			count, err := DeletePath(root, test.path)
+			if test.err {
+				if err == nil {
+					t.Errorf("DeletePath() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeletePath() unexpected error: %s", err)
+			}
+			if count != test.count {
+				t.Errorf("DeletePath() wrong count: %d, expected: %d", count, test.count)
+			}
+			if !equal(root, Must(Unmarshal([]byte(test.expected)))) {
+				t.Errorf("DeletePath() wrong result: %s, expected: %s", root, test.expected)
+			}
+		})
+	}
+}
+
+func TestUpdatePath(t *testing.T) {
+	double := func(node *Node) (*Node, error) {
+		if node.IsNumeric() {
+			return NumericNode("", node.MustNumeric()*2), nil
+		}
+		return node, nil
+	}
+	remove := func(node *Node) (*Node, error) {
+		if node.IsNumeric() && node.MustNumeric() > 2 {
+			return nil, nil
+		}
+		return node, nil
+	}
+	tests := []struct {
+		name     string
+		path     string
+		fn       func(node *Node) (*Node, error)
+		expected string
+		count    int
+		err      bool
+	}{
+		{name: "replace", path: "$..*", fn: double, expected: `{"a":[2,4,6,8],"b":{"c":[4,{"d":6}],"d":8}}`, count: 11},
+		{name: "duplicates", path: "$.a[0,0,1]", fn: double, expected: `{"a":[2,4,3,4],"b":{"c":[2,{"d":3}],"d":4}}`, count: 2},
+		{name: "remove", path: "$..*", fn: remove, expected: `{"a":[1,2],"b":{"c":[2,{}]}}`, count: 11},
+		{name: "children first", path: "$..c", fn: func(node *Node) (*Node, error) {
+			if node.IsArray() {
+				return NumericNode("", float64(node.Size())), nil
+			}
+			return node, nil
+		}, expected: `{"a":[1,2,3,4],"b":{"c":2,"d":4}}`, count: 1},
+		{name: "removed parent", path: "$.b..*", fn: func(node *Node) (*Node, error) {
+			if node.Key() == "d" {
+				return nil, nil
+			}
+			return node, nil
+		}, expected: `{"a":[1,2,3,4],"b":{"c":[2,{}]}}`, count: 5},
+		{name: "error", path: "$.a[*]", fn: func(node *Node) (*Node, error) {
+			return nil, errorRequest("stop")
+		}, err: true},
+		{name: "root", path: "$", fn: func(*Node) (*Node, error) { return nil, nil }, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": [1, 2, 3, 4], "b": {"c": [2, {"d": 3}], "d": 4}}`)))
+			count, err := UpdatePath(root, test.path, test.fn)
+			if test.err {
+				if err == nil {
+					t.Errorf("UpdatePath() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdatePath() unexpected error: %s", err)
+			}
+			if count != test.count {
+				t.Errorf("UpdatePath() wrong count: %d, expected: %d", count, test.count)
+			}
+			if !equal(root, Must(Unmarshal([]byte(test.expected)))) {
+				t.Errorf("UpdatePath() wrong result: %s, expected: %s", root, test.expected)
+			}
+		})
+	}
+}
+
+func ExampleDeletePath() {
+	root := Must(Unmarshal([]byte(`{"items": [1, -2, 3, -4, -5]}`)))
+	count, err := DeletePath(root, "$.items[?(@ < 0)]")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(count, root)
+	// Output:
+	// 3 {"items":[1,3]}
+}
@@ -0,0 +1,157 @@
+package ajson
+
+import (
+	"strconv"
+	"strings"
+)
+
+// This file contains the support of JSON Pointer (RFC 6901): https://tools.ietf.org/html/rfc6901
+
+// Pointer returns JSON Pointer of the node from the root of its document, e.g. "/store/book/0/title". Empty string is
+// the pointer of the root.
+func (n *Node) Pointer() string {
+	if n == nil || n.parent == nil {
+		return ""
+	}
+	if n.key != nil {
+		return n.parent.Pointer() + "/" + escapePointer(*n.key)
+	}
+	return n.parent.Pointer() + "/" + strconv.Itoa(n.Index())
+}
+
+// GetPointer returns the node by JSON Pointer, relative to the current node.
+//
+// Example:
+//
+// 	title, err := root.GetPointer("/store/book/0/title")
+func (n *Node) GetPointer(pointer string) (*Node, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return n.resolvePointer(pointer, tokens)
+}
+
+// SetPointer sets the clone of the value by JSON Pointer, relative to the current node: the existing value will be
+// replaced, the new key will be added into the object, and the token "-" (or the index equal to the size of the array)
+// will append the value to the array. Parent of the target must exist. Empty pointer replaces the current node.
+func (n *Node) SetPointer(pointer string, value *Node) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errorRequest("value is nil")
+	}
+	if len(tokens) == 0 {
+		return n.SetNode(value)
+	}
+	parent, err := n.resolvePointer(pointer, tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+	token := tokens[len(tokens)-1]
+	switch parent.Type() {
+	case Object:
+		if current, ok := parent.children[token]; ok {
+			return current.SetNode(value)
+		}
+		return parent.AppendObject(token, value.Clone())
+	case Array:
+		if token == "-" {
+			return parent.AppendArray(value.Clone())
+		}
+		index, err := pointerIndex(pointer, token, len(parent.children))
+		if err != nil {
+			return err
+		}
+		if index == len(parent.children) {
+			return parent.AppendArray(value.Clone())
+		}
+		return parent.children[strconv.Itoa(index)].SetNode(value)
+	}
+	return errorRequest("wrong pointer '%s': parent is not a container", pointer)
+}
+
+// DeletePointer removes the node by JSON Pointer, relative to the current node. The current node itself can't be
+// removed.
+func (n *Node) DeletePointer(pointer string) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errorRequest("wrong pointer '%s': root can't be deleted", pointer)
+	}
+	node, err := n.resolvePointer(pointer, tokens)
+	if err != nil {
+		return err
+	}
+	return node.parent.remove(node)
+}
+
+// resolvePointer returns the node by the parsed tokens of the pointer
+func (n *Node) resolvePointer(pointer string, tokens []string) (*Node, error) {
+	if n == nil {
+		return nil, errorUnparsed()
+	}
+	node := n
+	for _, token := range tokens {
+		var (
+			child *Node
+			ok    bool
+		)
+		switch node.Type() {
+		case Object:
+			child, ok = node.children[token]
+		case Array:
+			index, err := pointerIndex(pointer, token, len(node.children)-1)
+			if err != nil {
+				return nil, err
+			}
+			child, ok = node.children[strconv.Itoa(index)]
+		}
+		if !ok {
+			return nil, errorRequest("wrong pointer '%s': '%s' not found", pointer, token)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// parsePointer returns unescaped reference tokens of the pointer
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if pointer[0] != division {
+		return nil, errorRequest("wrong pointer '%s': must start with '/'", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, errorRequest("wrong pointer '%s': wrong escape sequence in '%s'", pointer, token)
+			}
+		}
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// pointerIndex returns the array index of the token, it can't be greater than max
+func pointerIndex(pointer, token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, errorRequest("wrong pointer '%s': wrong array index '%s'", pointer, token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > max {
+		return 0, errorRequest("wrong pointer '%s': '%s' not found", pointer, token)
+	}
+	return index, nil
+}
+
+// escapePointer escapes the reference token of the pointer
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
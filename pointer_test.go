@@ -0,0 +1,172 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNode_Pointer(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a/b": [{"m~n": 1}], "": {"": 2}}`)))
+	tests := []struct {
+		node     *Node
+		expected string
+	}{
+		{node: root, expected: ""},
+		{node: root.MustKey("a/b"), expected: "/a~1b"},
+		{node: root.MustKey("a/b").MustIndex(0).MustKey("m~n"), expected: "/a~1b/0/m~0n"},
+		{node: root.MustKey("").MustKey(""), expected: "//"},
+		{node: nil, expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			if result := test.node.Pointer(); result != test.expected {
+				t.Errorf("Pointer() wrong result: %s, expected: %s", result, test.expected)
+			}
+			if test.node == nil {
+				return
+			}
+			if node, err := root.GetPointer(test.expected); err != nil || node != test.node {
+				t.Errorf("GetPointer() wrong result: %v, %v", node, err)
+			}
+		})
+	}
+}
+
+func TestNode_GetPointer(t *testing.T) {
+	// examples from RFC 6901
+	root := Must(Unmarshal([]byte(`{
+		"foo": ["bar", "baz"],
+		"": 0,
+		"a/b": 1,
+		"c%d": 2,
+		"e^f": 3,
+		"g|h": 4,
+		"i\\j": 5,
+		"k\"l": 6,
+		" ": 7,
+		"m~n": 8
+	}`)))
+	tests := []struct {
+		pointer  string
+		expected string
+	}{
+		{pointer: "", expected: root.String()},
+		{pointer: "/foo", expected: `["bar", "baz"]`},
+		{pointer: "/foo/0", expected: `"bar"`},
+		{pointer: "/", expected: `0`},
+		{pointer: "/a~1b", expected: `1`},
+		{pointer: "/c%d", expected: `2`},
+		{pointer: "/e^f", expected: `3`},
+		{pointer: "/g|h", expected: `4`},
+		{pointer: "/i\\j", expected: `5`},
+		{pointer: "/k\"l", expected: `6`},
+		{pointer: "/ ", expected: `7`},
+		{pointer: "/m~0n", expected: `8`},
+	}
+	for _, test := range tests {
+		t.Run(test.pointer, func(t *testing.T) {
+			node, err := root.GetPointer(test.pointer)
+			if err != nil {
+				t.Fatalf("GetPointer() unexpected error: %s", err)
+			}
+			if node.String() != test.expected {
+				t.Errorf("GetPointer() wrong result: %s, expected: %s", node, test.expected)
+			}
+		})
+	}
+
+	for _, pointer := range []string{"foo", "/bar", "/foo/2", "/foo/-", "/foo/01", "/foo/-1", "/foo/a", "/foo/0/x", "/m~2n", "/m~"} {
+		t.Run(pointer, func(t *testing.T) {
+			if node, err := root.GetPointer(pointer); err == nil {
+				t.Errorf("GetPointer() expected error, got: %s", node)
+			}
+		})
+	}
+}
+
+func TestNode_SetPointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		pointer  string
+		value    string
+		expected string
+		err      bool
+	}{
+		{name: "replace key", pointer: "/a", value: `true`, expected: `{"a":true,"b":[1, 2]}`},
+		{name: "add key", pointer: "/c~1d", value: `{"e": null}`, expected: `{"a":1,"b":[1, 2],"c/d":{"e": null}}`},
+		{name: "replace index", pointer: "/b/0", value: `3`, expected: `{"a":1,"b":[3,2]}`},
+		{name: "append with dash", pointer: "/b/-", value: `3`, expected: `{"a":1,"b":[1,2,3]}`},
+		{name: "append with index", pointer: "/b/2", value: `3`, expected: `{"a":1,"b":[1,2,3]}`},
+		{name: "root", pointer: "", value: `[]`, expected: `[]`},
+		{name: "out of range", pointer: "/b/3", value: `3`, err: true},
+		{name: "missing parent", pointer: "/c/d", value: `3`, err: true},
+		{name: "scalar parent", pointer: "/a/b", value: `3`, err: true},
+		{name: "wrong pointer", pointer: "a", value: `3`, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": 1, "b": [1, 2]}`)))
+			value := Must(Unmarshal([]byte(test.value)))
+			err := root.SetPointer(test.pointer, value)
+			if test.err {
+				if err == nil {
+					t.Errorf("SetPointer() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetPointer() unexpected error: %s", err)
+			}
+			if result := root.String(); result != test.expected {
+				t.Errorf("SetPointer() wrong result: %s, expected: %s", result, test.expected)
+			}
+			if value.Parent() != nil {
+				t.Errorf("SetPointer() value must be cloned")
+			}
+		})
+	}
+}
+
+func TestNode_DeletePointer(t *testing.T) {
+	tests := []struct {
+		name     string
+		pointer  string
+		expected string
+		err      bool
+	}{
+		{name: "key", pointer: "/a", expected: `{"b":[1, 2]}`},
+		{name: "index", pointer: "/b/0", expected: `{"a":1,"b":[2]}`},
+		{name: "root", pointer: "", err: true},
+		{name: "missing", pointer: "/c", err: true},
+		{name: "dash", pointer: "/b/-", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := Must(Unmarshal([]byte(`{"a": 1, "b": [1, 2]}`)))
+			err := root.DeletePointer(test.pointer)
+			if test.err {
+				if err == nil {
+					t.Errorf("DeletePointer() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeletePointer() unexpected error: %s", err)
+			}
+			if result := root.String(); result != test.expected {
+				t.Errorf("DeletePointer() wrong result: %s, expected: %s", result, test.expected)
+			}
+		})
+	}
+}
+
+func ExampleNode_GetPointer() {
+	root := Must(Unmarshal(jsonExample))
+	node, err := root.GetPointer("/store/book/1/title")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s: %s", node.Pointer(), node.MustString())
+	// Output:
+	// /store/book/1/title: Sword of Honour
+}
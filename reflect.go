@@ -0,0 +1,689 @@
+package ajson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	numberType          = reflect.TypeOf(json.Number(""))
+	marshalerType       = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Decode stores the value of the node into the value pointed to by v, without marshaling the node. Rules are the same
+// as for encoding/json.Unmarshal:
+//
+//   - struct fields are matched by the `json:"name"` tag or by the field name, case-insensitive; option `string`
+//     allows the value of a bool, numeric or string field to be given as a JSON string; unknown keys are ignored;
+//   - json.Unmarshaler is called with the marshaled node, encoding.TextUnmarshaler is called for strings;
+//   - Null sets pointers, interfaces, maps and slices to nil, and leaves other values unchanged;
+//   - interface{} gets the result of Node.Unpack;
+//   - []byte is decoded from the base64 string, json.Number gets the literal of the number.
+//
+// Integers are taken with the exact value of the literal (see Node.GetInt64). If the node can't be stored into the Go
+// value, WrongTarget error with the path of the node is returned.
+func (n *Node) Decode(v interface{}) error {
+	if n == nil {
+		return errorUnparsed()
+	}
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return unsupportedType(v)
+	}
+	return n.decode(value, false)
+}
+
+// decode stores the value of the node into the value; quoted is set for the fields with the `string` option
+func (n *Node) decode(value reflect.Value, quoted bool) error {
+	if quoted && n._type != Null {
+		return n.decodeQuoted(value)
+	}
+	unmarshaler, textUnmarshaler, value := indirect(value, n._type == Null)
+	if unmarshaler != nil {
+		data, err := Marshal(n)
+		if err != nil {
+			return err
+		}
+		return unmarshaler.UnmarshalJSON(data)
+	}
+	if textUnmarshaler != nil {
+		if n._type != String {
+			return errorTarget(n, value.Type())
+		}
+		return textUnmarshaler.UnmarshalText([]byte(n.MustString()))
+	}
+	if value.Kind() == reflect.Interface && value.NumMethod() == 0 {
+		if n._type == Null {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+		unpacked, err := n.Unpack()
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(unpacked))
+		return nil
+	}
+
+	switch n._type {
+	case Null:
+		switch value.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			value.Set(reflect.Zero(value.Type()))
+		}
+		return nil
+	case Bool:
+		if value.Kind() != reflect.Bool {
+			return errorTarget(n, value.Type())
+		}
+		value.SetBool(n.MustBool())
+		return nil
+	case Numeric:
+		return n.decodeNumeric(value)
+	case String:
+		return n.decodeString(value)
+	case Array:
+		return n.decodeArray(value)
+	case Object:
+		switch value.Kind() {
+		case reflect.Map:
+			return n.decodeMap(value)
+		case reflect.Struct:
+			return n.decodeStruct(value)
+		}
+	}
+	return errorTarget(n, value.Type())
+}
+
+// decodeQuoted decodes the value, given as a JSON string, for the fields with the `string` option
+func (n *Node) decodeQuoted(value reflect.Value) error {
+	if n._type != String {
+		return errorTarget(n, value.Type())
+	}
+	inner, err := Unmarshal([]byte(n.MustString()))
+	if err != nil || inner.isContainer() {
+		return errorTarget(n, value.Type())
+	}
+	if err = inner.decode(value, false); err != nil {
+		return errorTarget(n, value.Type())
+	}
+	return nil
+}
+
+func (n *Node) decodeNumeric(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, err := n.GetInt64()
+		if err != nil || value.OverflowInt(integer) {
+			return errorTarget(n, value.Type())
+		}
+		value.SetInt(integer)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		integer, err := n.GetUint64()
+		if err != nil || value.OverflowUint(integer) {
+			return errorTarget(n, value.Type())
+		}
+		value.SetUint(integer)
+	case reflect.Float32, reflect.Float64:
+		float, err := n.GetNumeric()
+		if err != nil || value.OverflowFloat(float) {
+			return errorTarget(n, value.Type())
+		}
+		value.SetFloat(float)
+	case reflect.String:
+		if value.Type() != numberType {
+			return errorTarget(n, value.Type())
+		}
+		literal, err := n.literal()
+		if err != nil {
+			return err
+		}
+		value.SetString(literal)
+	default:
+		return errorTarget(n, value.Type())
+	}
+	return nil
+}
+
+func (n *Node) decodeString(value reflect.Value) error {
+	text := n.MustString()
+	switch value.Kind() {
+	case reflect.String:
+		if value.Type() == numberType && !strictNumeric([]byte(text)) {
+			return errorTarget(n, value.Type())
+		}
+		value.SetString(text)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.Uint8 {
+			return errorTarget(n, value.Type())
+		}
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return errorTarget(n, value.Type())
+		}
+		value.SetBytes(data)
+	default:
+		return errorTarget(n, value.Type())
+	}
+	return nil
+}
+
+func (n *Node) decodeArray(value reflect.Value) error {
+	children := n.Inheritors()
+	switch value.Kind() {
+	case reflect.Slice:
+		result := reflect.MakeSlice(value.Type(), len(children), len(children))
+		for i, child := range children {
+			if err := child.decode(result.Index(i), false); err != nil {
+				return err
+			}
+		}
+		value.Set(result)
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if i < len(children) {
+				if err := children[i].decode(value.Index(i), false); err != nil {
+					return err
+				}
+			} else {
+				value.Index(i).Set(reflect.Zero(value.Type().Elem()))
+			}
+		}
+	default:
+		return errorTarget(n, value.Type())
+	}
+	return nil
+}
+
+func (n *Node) decodeMap(value reflect.Value) error {
+	_type := value.Type()
+	keyType := _type.Key()
+	switch keyType.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+	default:
+		if !reflect.PtrTo(keyType).Implements(textUnmarshalerType) {
+			return errorTarget(n, _type)
+		}
+	}
+	if value.IsNil() {
+		value.Set(reflect.MakeMap(_type))
+	}
+	for _, key := range n.Keys() {
+		child := n.children[key]
+		element := reflect.New(_type.Elem()).Elem()
+		if err := child.decode(element, false); err != nil {
+			return err
+		}
+		mapKey, ok := decodeKey(key, keyType)
+		if !ok {
+			return errorTarget(child, keyType)
+		}
+		value.SetMapIndex(mapKey, element)
+	}
+	return nil
+}
+
+func (n *Node) decodeStruct(value reflect.Value) error {
+	fields := structFields(value.Type(), nil, make(map[string]bool))
+	for _, key := range n.Keys() {
+		current := matchField(fields, key)
+		if current == nil {
+			continue
+		}
+		target, ok := fieldByIndex(value, current.index)
+		if !ok {
+			return errorTarget(n.children[key], value.Type())
+		}
+		if err := n.children[key].decode(target, current.quoted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indirect walks down the pointers, allocating them if needed, until it finds json.Unmarshaler,
+// encoding.TextUnmarshaler, or a non-pointer value. If null is set, it stops at the last pointer, so it can be set to
+// nil.
+func indirect(value reflect.Value, null bool) (json.Unmarshaler, encoding.TextUnmarshaler, reflect.Value) {
+	if value.Kind() != reflect.Ptr && value.Type().Name() != "" && value.CanAddr() {
+		value = value.Addr()
+	}
+	for {
+		if value.Kind() == reflect.Interface && !value.IsNil() {
+			element := value.Elem()
+			if element.Kind() == reflect.Ptr && !element.IsNil() && (!null || element.Elem().Kind() == reflect.Ptr) {
+				value = element
+				continue
+			}
+		}
+		if value.Kind() != reflect.Ptr {
+			break
+		}
+		if null && value.CanSet() {
+			break
+		}
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		if value.Type().NumMethod() > 0 && value.CanInterface() {
+			if unmarshaler, ok := value.Interface().(json.Unmarshaler); ok {
+				return unmarshaler, nil, reflect.Value{}
+			}
+			if !null {
+				if unmarshaler, ok := value.Interface().(encoding.TextUnmarshaler); ok {
+					return nil, unmarshaler, value
+				}
+			}
+		}
+		value = value.Elem()
+	}
+	return nil, nil, value
+}
+
+// decodeKey converts the object key into the key of the map
+func decodeKey(key string, _type reflect.Type) (reflect.Value, bool) {
+	if reflect.PtrTo(_type).Implements(textUnmarshalerType) {
+		result := reflect.New(_type)
+		if err := result.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return result, false
+		}
+		return result.Elem(), true
+	}
+	result := reflect.New(_type).Elem()
+	switch _type.Kind() {
+	case reflect.String:
+		result.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || result.OverflowInt(integer) {
+			return result, false
+		}
+		result.SetInt(integer)
+	default:
+		integer, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || result.OverflowUint(integer) {
+			return result, false
+		}
+		result.SetUint(integer)
+	}
+	return result, true
+}
+
+// field is the description of the struct field for Node.Decode
+type field struct {
+	name      string
+	index     []int
+	quoted    bool
+	omitEmpty bool
+}
+
+// structFields returns fields of the struct in the order of declaration, fields of the embedded structs are included
+// at the place of the embedded struct, if they are not shadowed by the fields of the outer struct
+func structFields(_type reflect.Type, index []int, names map[string]bool) (result []field) {
+	var embedded []reflect.StructField
+	for i := 0; i < _type.NumField(); i++ {
+		current := _type.Field(i)
+		tag := current.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.IndexByte(tag, coma); comma != -1 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		fieldType := current.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if current.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded = append(embedded, current)
+			continue
+		}
+		if current.PkgPath != "" { // unexported
+			continue
+		}
+		if name == "" {
+			name = current.Name
+		}
+		if names[name] {
+			continue
+		}
+		names[name] = true
+		quoted, omitEmpty := false, false
+		for _, option := range strings.Split(options, ",") {
+			if option == "omitempty" {
+				omitEmpty = true
+			}
+			if option == "string" {
+				switch fieldType.Kind() {
+				case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+					reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+					quoted = true
+				}
+			}
+		}
+		result = append(result, field{
+			name:      name,
+			index:     append(append([]int{}, index...), i),
+			quoted:    quoted,
+			omitEmpty: omitEmpty,
+		})
+	}
+	for _, current := range embedded {
+		fieldType := current.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		result = append(result, structFields(fieldType, append(append([]int{}, index...), current.Index...), names)...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].index, result[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return result
+}
+
+// matchField returns the field with the exact name or, if there is no such one, with the case-insensitive match
+func matchField(fields []field, name string) *field {
+	var fold *field
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+		if fold == nil && strings.EqualFold(fields[i].name, name) {
+			fold = &fields[i]
+		}
+	}
+	return fold
+}
+
+// fieldByIndex returns the field of the struct, nil pointers of the embedded structs are allocated
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !value.CanSet() {
+					return value, false
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, true
+}
+
+// FromValue creates the Node from the Go value by reflection, with the same rules as encoding/json.Marshal:
+//
+//   - struct fields are named by the `json:"name"` tag or by the field name, options `omitempty` and `string` are
+//     supported, fields are added in the order of declaration;
+//   - json.Marshaler and encoding.TextMarshaler are used, if value implements them;
+//   - keys of maps are sorted, nil pointers, interfaces, maps and slices are Null;
+//   - []byte is encoded as the base64 string, integers and json.Number keep the exact value (see Int64Node).
+//
+// Result has no parent, so it can be added into the document with Node.AppendObject, Node.AppendArray or Node.SetNode.
+// Channels, functions, complex numbers, NaN and infinite floats can't be converted, as well as values, that refer to
+// themselves: UnsupportedType error is returned.
+func FromValue(value interface{}) (*Node, error) {
+	return fromValue(reflect.ValueOf(value), false, make(visits))
+}
+
+// visit is the pointer, map or slice, that is being converted by FromValue
+type visit struct {
+	pointer uintptr
+	_type   reflect.Type
+	length  int
+}
+
+// visits are the values, that are being converted by FromValue, to detect the cycles
+type visits map[visit]bool
+
+// enter marks the value as being converted, it returns false if the value is already being converted
+func (v visits) enter(value reflect.Value) bool {
+	key := visit{pointer: value.Pointer(), _type: value.Type()}
+	if value.Kind() == reflect.Slice {
+		key.length = value.Len()
+	}
+	if v[key] {
+		return false
+	}
+	v[key] = true
+	return true
+}
+
+// leave removes the mark of the converted value
+func (v visits) leave(value reflect.Value) {
+	key := visit{pointer: value.Pointer(), _type: value.Type()}
+	if value.Kind() == reflect.Slice {
+		key.length = value.Len()
+	}
+	delete(v, key)
+}
+
+// fromValue creates the Node from the value; quoted is set for the fields with the `string` option
+func fromValue(value reflect.Value, quoted bool, visiting visits) (*Node, error) {
+	if !value.IsValid() {
+		return NullNode(""), nil
+	}
+	if value.Kind() != reflect.Ptr && value.CanAddr() && reflect.PtrTo(value.Type()).Implements(marshalerType) {
+		value = value.Addr()
+	}
+	if value.Type().Implements(marshalerType) {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return NullNode(""), nil
+		}
+		data, err := value.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return Unmarshal(data)
+	}
+	if value.Kind() != reflect.Ptr && value.CanAddr() && reflect.PtrTo(value.Type()).Implements(textMarshalerType) {
+		value = value.Addr()
+	}
+	if value.Type().Implements(textMarshalerType) {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return NullNode(""), nil
+		}
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return StringNode("", string(text)), nil
+	}
+
+	var (
+		result *Node
+		err    error
+	)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return NullNode(""), nil
+		}
+		if value.Kind() == reflect.Ptr {
+			if !visiting.enter(value) {
+				return nil, unsupportedType(value.Interface())
+			}
+			defer visiting.leave(value)
+		}
+		return fromValue(value.Elem(), quoted, visiting)
+	case reflect.Bool:
+		result = BoolNode("", value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		result = Int64Node("", value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		result = Uint64Node("", value.Uint())
+	case reflect.Float32, reflect.Float64:
+		float := value.Float()
+		if math.IsInf(float, 0) || math.IsNaN(float) {
+			return nil, unsupportedType(value.Interface())
+		}
+		result = literalNode("", strconv.FormatFloat(float, 'g', -1, value.Type().Bits()))
+	case reflect.String:
+		if value.Type() == numberType {
+			literal := value.String()
+			if literal == "" {
+				literal = "0"
+			}
+			if result, err = NumericLiteralNode("", literal); err != nil {
+				return nil, err
+			}
+		} else {
+			result = StringNode("", value.String())
+		}
+	case reflect.Slice:
+		if value.IsNil() {
+			return NullNode(""), nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 && !reflect.PtrTo(value.Type().Elem()).Implements(marshalerType) &&
+			!reflect.PtrTo(value.Type().Elem()).Implements(textMarshalerType) {
+			return StringNode("", base64.StdEncoding.EncodeToString(value.Bytes())), nil
+		}
+		if !visiting.enter(value) {
+			return nil, unsupportedType(value.Interface())
+		}
+		defer visiting.leave(value)
+		return fromArray(value, visiting)
+	case reflect.Array:
+		return fromArray(value, visiting)
+	case reflect.Map:
+		return fromMap(value, visiting)
+	case reflect.Struct:
+		return fromStruct(value, visiting)
+	default:
+		return nil, unsupportedType(value.Interface())
+	}
+	if quoted {
+		data, err := Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		return StringNode("", string(data)), nil
+	}
+	return result, nil
+}
+
+func fromArray(value reflect.Value, visiting visits) (*Node, error) {
+	children := make([]*Node, value.Len())
+	for i := range children {
+		child, err := fromValue(value.Index(i), false, visiting)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = child
+	}
+	return ArrayNode("", children), nil
+}
+
+func fromMap(value reflect.Value, visiting visits) (*Node, error) {
+	if value.IsNil() {
+		return NullNode(""), nil
+	}
+	if !visiting.enter(value) {
+		return nil, unsupportedType(value.Interface())
+	}
+	defer visiting.leave(value)
+	children := make(map[string]*Node, value.Len())
+	iterator := value.MapRange()
+	for iterator.Next() {
+		key, err := encodeKey(iterator.Key())
+		if err != nil {
+			return nil, err
+		}
+		child, err := fromValue(iterator.Value(), false, visiting)
+		if err != nil {
+			return nil, err
+		}
+		children[key] = child
+	}
+	return ObjectNode("", children), nil
+}
+
+func fromStruct(value reflect.Value, visiting visits) (*Node, error) {
+	result := ObjectNode("", nil)
+	for _, current := range structFields(value.Type(), nil, make(map[string]bool)) {
+		target, ok := fieldValue(value, current.index)
+		if !ok || (current.omitEmpty && isEmptyValue(target)) {
+			continue
+		}
+		child, err := fromValue(target, current.quoted, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if err = result.AppendObject(current.name, child); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// encodeKey converts the key of the map into the object key
+func encodeKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if key.Type().Implements(textMarshalerType) {
+		if key.Kind() == reflect.Ptr && key.IsNil() {
+			return "", nil
+		}
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", unsupportedType(key.Interface())
+}
+
+// fieldValue returns the field of the struct, or false, if it's a field of the nil embedded struct
+func fieldValue(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return value, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, true
+}
+
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return value.IsNil()
+	}
+	return false
+}
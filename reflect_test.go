@@ -0,0 +1,367 @@
+package ajson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type decodeBase struct {
+	ID      int64 `json:"id"`
+	Comment string
+}
+
+type decodeItem struct {
+	decodeBase
+	Name     string            `json:"name,omitempty"`
+	Price    float64           `json:"price"`
+	Count    uint8             `json:"count,string"`
+	Tags     []string          `json:"tags"`
+	Attrs    map[string]int    `json:"attrs"`
+	Pointer  *bool             `json:"pointer"`
+	Any      interface{}       `json:"any"`
+	Raw      json.RawMessage   `json:"raw"`
+	Number   json.Number       `json:"number"`
+	Time     time.Time         `json:"time"`
+	IP       net.IP            `json:"ip"`
+	Data     []byte            `json:"data"`
+	Fixed    [2]int            `json:"fixed"`
+	Indexed  map[int]string    `json:"indexed"`
+	Skipped  string            `json:"-"`
+	Children []*decodeItem     `json:"children"`
+	Nested   map[string][]bool `json:"nested"`
+	hidden   string
+}
+
+func TestNode_Decode(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{
+		"id": 9007199254740993,
+		"comment": "case insensitive",
+		"name": "item",
+		"price": 1.5,
+		"count": "12",
+		"tags": ["a", "b"],
+		"attrs": {"x": 1, "y": 2},
+		"pointer": true,
+		"any": {"a": [1, "b", null]},
+		"raw": {"keep": [1, 2]},
+		"number": 12345678901234567890,
+		"time": "2020-01-02T03:04:05Z",
+		"ip": "127.0.0.1",
+		"data": "aGVsbG8=",
+		"fixed": [1, 2, 3],
+		"indexed": {"1": "one", "2": "two"},
+		"Skipped": "value",
+		"-": "value",
+		"hidden": "value",
+		"unknown": "value",
+		"children": [{"name": "child"}, null],
+		"nested": {"a": [true, false]}
+	}`)))
+	var result decodeItem
+	if err := root.Decode(&result); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	pointer := true
+	expected := decodeItem{
+		decodeBase: decodeBase{ID: 9007199254740993, Comment: "case insensitive"},
+		Name:       "item",
+		Price:      1.5,
+		Count:      12,
+		Tags:       []string{"a", "b"},
+		Attrs:      map[string]int{"x": 1, "y": 2},
+		Pointer:    &pointer,
+		Any:        map[string]interface{}{"a": []interface{}{float64(1), "b", nil}},
+		Raw:        json.RawMessage(`{"keep": [1, 2]}`),
+		Number:     json.Number("12345678901234567890"),
+		Time:       time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		IP:         net.ParseIP("127.0.0.1"),
+		Data:       []byte("hello"),
+		Fixed:      [2]int{1, 2},
+		Indexed:    map[int]string{1: "one", 2: "two"},
+		Children:   []*decodeItem{{Name: "child"}, nil},
+		Nested:     map[string][]bool{"a": {true, false}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Decode() wrong result:\n%#v\nexpected:\n%#v", result, expected)
+	}
+}
+
+func TestNode_Decode_null(t *testing.T) {
+	value := 5
+	result := struct {
+		Pointer *int
+		Slice   []int
+		Integer int
+	}{Pointer: &value, Slice: []int{1}, Integer: 7}
+	if err := Must(Unmarshal([]byte(`{"Pointer": null, "Slice": null, "Integer": null}`))).Decode(&result); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if result.Pointer != nil || result.Slice != nil || result.Integer != 7 {
+		t.Errorf("Decode() wrong result: %#v", result)
+	}
+}
+
+func TestNode_Decode_error(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		target interface{}
+		path   string
+	}{
+		{name: "string to int", input: `{"a": [1, "2"]}`, target: new(map[string][]int), path: "$['a'][1]"},
+		{name: "overflow", input: `[256]`, target: new([]uint8), path: "$[0]"},
+		{name: "negative to uint", input: `[-1]`, target: new([]uint), path: "$[0]"},
+		{name: "fraction to int", input: `1.5`, target: new(int), path: "$"},
+		{name: "float32 overflow", input: `1e300`, target: new(float32), path: "$"},
+		{name: "object to slice", input: `{}`, target: new([]int), path: "$"},
+		{name: "bool to string", input: `true`, target: new(string), path: "$"},
+		{name: "wrong base64", input: `"!"`, target: new([]byte), path: "$"},
+		{name: "wrong quoted", input: `{"count": "x"}`, target: new(decodeItem), path: "$['count']"},
+		{name: "not quoted", input: `{"count": 1}`, target: new(decodeItem), path: "$['count']"},
+		{name: "wrong map key", input: `{"x": "y"}`, target: new(map[int]string), path: "$['x']"},
+		{name: "text unmarshaler", input: `{"ip": 1}`, target: new(decodeItem), path: "$['ip']"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Must(Unmarshal([]byte(test.input))).Decode(test.target)
+			current, ok := err.(Error)
+			if !ok || current.Type != WrongTarget {
+				t.Fatalf("Decode() wrong error: %#v", err)
+			}
+			if current.Message != test.path {
+				t.Errorf("Decode() wrong path: %s, expected: %s", current.Message, test.path)
+			}
+		})
+	}
+
+	if err := Must(Unmarshal([]byte(`1`))).Decode(nil); err == nil {
+		t.Errorf("Decode(nil) expected error")
+	}
+	var value int
+	if err := Must(Unmarshal([]byte(`1`))).Decode(value); err == nil {
+		t.Errorf("Decode(non-pointer) expected error")
+	}
+	if err := (*Node)(nil).Decode(&value); err == nil {
+		t.Errorf("(*Node)(nil).Decode() expected error")
+	}
+	if err := Must(Unmarshal([]byte(`1`))).Decode(&value); err != nil || value != 1 {
+		t.Errorf("Decode() wrong result: %d, %v", value, err)
+	}
+	if err := NumericNode("", math.Inf(1)).Decode(&value); err == nil {
+		t.Errorf("Decode() expected error")
+	}
+}
+
+func ExampleNode_Decode() {
+	type Book struct {
+		Title  string  `json:"title"`
+		Author string  `json:"author"`
+		Price  float64 `json:"price"`
+	}
+	root := Must(Unmarshal(jsonExample))
+	nodes, err := root.JSONPath("$.store.book[?(@.price < 10)]")
+	if err != nil {
+		panic(err)
+	}
+	books := make([]Book, len(nodes))
+	for i, node := range nodes {
+		if err = node.Decode(&books[i]); err != nil {
+			panic(err)
+		}
+	}
+	for _, book := range books {
+		fmt.Printf("%s: %s (%v)\n", book.Author, strings.ToUpper(book.Title), book.Price)
+	}
+	// Output:
+	// Nigel Rees: SAYINGS OF THE CENTURY (8.95)
+	// Herman Melville: MOBY DICK (8.99)
+}
+
+type fromValueMarshaler struct{}
+
+func (fromValueMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"custom": [1, 2]}`), nil
+}
+
+type fromValueText int
+
+func (value *fromValueText) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("text-%d", int(*value))), nil
+}
+
+func (value *fromValueText) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "text-%d", (*int)(value))
+	return err
+}
+
+func TestFromValue(t *testing.T) {
+	type Embedded struct {
+		Inner string `json:"inner"`
+	}
+	type value struct {
+		*Embedded
+		Name      string                 `json:"name"`
+		Empty     string                 `json:"empty,omitempty"`
+		Count     int                    `json:"count,string"`
+		ID        uint64                 `json:"id"`
+		Ratio     float32                `json:"ratio"`
+		Tags      []string               `json:"tags"`
+		Nil       []string               `json:"nil"`
+		Data      []byte                 `json:"data"`
+		Map       map[string]interface{} `json:"map"`
+		Indexed   map[int]bool           `json:"indexed"`
+		Pointer   *int                   `json:"pointer"`
+		Custom    fromValueMarshaler     `json:"custom"`
+		Text      fromValueText          `json:"text"`
+		Number    json.Number            `json:"number"`
+		Time      time.Time              `json:"time"`
+		Skipped   string                 `json:"-"`
+		Untagged  bool
+		unexposed string
+	}
+	input := value{
+		Embedded:  &Embedded{Inner: "embedded"},
+		Name:      "name",
+		Count:     12,
+		ID:        math.MaxUint64,
+		Ratio:     0.1,
+		Tags:      []string{"a", "b"},
+		Data:      []byte("hello"),
+		Map:       map[string]interface{}{"z": 1, "a": []int{1}},
+		Indexed:   map[int]bool{2: true, 1: false},
+		Text:      3,
+		Number:    "12345678901234567890",
+		Time:      time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Skipped:   "skipped",
+		Untagged:  true,
+		unexposed: "unexposed",
+	}
+	node, err := FromValue(&input)
+	if err != nil {
+		t.Fatalf("FromValue() unexpected error: %s", err)
+	}
+	result, err := Marshal(node)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %s", err)
+	}
+	expected, err := json.Marshal(&input)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %s", err)
+	}
+	compacted := compact(result)
+	if string(compacted) != string(expected) {
+		t.Errorf("FromValue() wrong result:\n%s\nexpected:\n%s", compacted, expected)
+	}
+
+	var decoded value
+	if err = node.Decode(&decoded); err != nil {
+		t.Fatalf("Decode() unexpected error: %s", err)
+	}
+	if decoded.ID != math.MaxUint64 || decoded.Count != 12 || decoded.Inner != "embedded" {
+		t.Errorf("Decode() wrong result: %#v", decoded)
+	}
+}
+
+func TestFromValue_primitives(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{name: "nil", value: nil, expected: `null`},
+		{name: "nil pointer", value: (*int)(nil), expected: `null`},
+		{name: "nil map", value: map[string]int(nil), expected: `null`},
+		{name: "bool", value: true, expected: `true`},
+		{name: "int8", value: int8(-8), expected: `-8`},
+		{name: "int64", value: int64(math.MinInt64), expected: `-9223372036854775808`},
+		{name: "float64", value: 1e21, expected: `1e+21`},
+		{name: "string", value: "<a\u2028>", expected: `"\u003ca\u2028\u003e"`},
+		{name: "array", value: [2]int{1, 2}, expected: `[1,2]`},
+		{name: "interface slice", value: []interface{}{1, "a", nil}, expected: `[1,"a",null]`},
+		{name: "empty map", value: map[int]int{}, expected: `{}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node, err := FromValue(test.value)
+			if err != nil {
+				t.Fatalf("FromValue() unexpected error: %s", err)
+			}
+			result, err := Marshal(node)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("FromValue() wrong result: %s, expected: %s", result, test.expected)
+			}
+		})
+	}
+}
+
+type cyclic struct {
+	Next *cyclic `json:"next"`
+}
+
+type cyclicSlice []cyclicSlice
+
+func TestFromValue_error(t *testing.T) {
+	pointer := &cyclic{}
+	pointer.Next = pointer
+	slice := cyclicSlice{nil}
+	slice[0] = slice
+	object := map[string]interface{}{}
+	object["self"] = object
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "channel", value: make(chan int)},
+		{name: "function", value: func() {}},
+		{name: "complex", value: complex(1, 2)},
+		{name: "NaN", value: math.NaN()},
+		{name: "infinity in slice", value: []float64{math.Inf(1)}},
+		{name: "struct key", value: map[struct{}]int{{}: 1}},
+		{name: "json.Number", value: json.Number("x")},
+		{name: "cyclic pointer", value: pointer},
+		{name: "cyclic slice", value: slice},
+		{name: "cyclic map", value: object},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if node, err := FromValue(test.value); err == nil {
+				t.Errorf("FromValue() expected error, got: %s", node)
+			}
+		})
+	}
+	shared := &cyclic{}
+	if node, err := FromValue([]*cyclic{shared, shared}); err != nil || node.String() != `[{"next":null},{"next":null}]` {
+		t.Errorf("FromValue() wrong result for the shared pointer: %s, %v", node, err)
+	}
+}
+
+func ExampleFromValue() {
+	type Currency struct {
+		Code string  `json:"code"`
+		Rate float64 `json:"rate,omitempty"`
+	}
+	root := Must(Unmarshal([]byte(`{"price": 8.95}`)))
+	node, err := FromValue(Currency{Code: "EUR"})
+	if err != nil {
+		panic(err)
+	}
+	if err = root.AppendObject("currency", node); err != nil {
+		panic(err)
+	}
+	result, err := Marshal(root)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s", result)
+	// Output:
+	// {"price":8.95,"currency":{"code":"EUR"}}
+}
@@ -0,0 +1,398 @@
+package ajson
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strconv"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	. "github.com/spyzhov/ajson/internal"
+)
+
+// This file contains JSON5 extensions of the parser, see Options.Relaxed. Relaxed tokens are processed before the
+// state transition table, all other tokens will be processed in the strict way.
+
+var (
+	_infinity = []byte("Infinity")
+	_nan      = []byte("NaN")
+
+	// additional JSON5 whitespaces: NBSP, BOM, LS, PS
+	_spaces = [][]byte{{0xC2, 0xA0}, {0xEF, 0xBB, 0xBF}, {0xE2, 0x80, 0xA8}, {0xE2, 0x80, 0xA9}}
+)
+
+// skip skips whitespaces and comments. Source of container with a comment or non-JSON whitespace is not a valid JSON,
+// so container will be marked as dirty.
+func (p *parser) skip(final bool) error {
+	var (
+		buf    = p.buf
+		strict bool
+		size   int
+	)
+	for buf.index < buf.length {
+		strict, size = relaxedSpace(buf.data[buf.index:])
+		if size == 0 && buf.data[buf.index] == division {
+			size = p.comment(final)
+			if size == 0 {
+				return nil
+			}
+			if size < 0 {
+				if final {
+					buf.index = buf.length
+					return buf.errorEOF()
+				}
+				return io.EOF
+			}
+		}
+		if size == 0 {
+			return nil
+		}
+		if !strict && p.current != nil && !p.current.ready() {
+			p.current.mark()
+		}
+		buf.index += size
+	}
+	return io.EOF
+}
+
+// comment returns the size of the comment, started at the current index: 0 if there is no comment, -1 if the comment is
+// not finished yet.
+func (p *parser) comment(final bool) int {
+	buf := p.buf
+	if buf.index+1 >= buf.length {
+		if final {
+			return 0
+		}
+		return -1
+	}
+	switch buf.data[buf.index+1] {
+	case division:
+		end := bytes.IndexByte(buf.data[buf.index:], skipN)
+		if end == -1 {
+			if !final {
+				return -1
+			}
+			return buf.length - buf.index
+		}
+		return end + 1
+	case asterisk:
+		end := bytes.Index(buf.data[buf.index+2:], []byte("*/"))
+		if end == -1 {
+			return -1
+		}
+		return end + 4
+	}
+	return 0
+}
+
+// relaxed processes JSON5 tokens, started at the current index: keys, strings and numbers. Returns true, if token was
+// processed.
+func (p *parser) relaxed() (ok bool, err error) {
+	buf := p.buf
+	c := buf.data[buf.index]
+	switch buf.state {
+	case KE, OB:
+		if c == bracesR {
+			if buf.state == KE { // trailing comma
+				buf.state = OK
+				p.current.mark()
+			}
+			return false, nil
+		}
+		if p.current == nil || !p.current.IsObject() || p.key != nil {
+			return false, nil
+		}
+		var (
+			key   string
+			start = buf.index
+		)
+		if c == quotes || c == quote {
+			key, err = p.string(c)
+			if err != nil {
+				return false, err
+			}
+			if c != quotes || !strictString(buf.data[start:buf.index+1]) {
+				p.current.mark()
+			}
+		} else if identifier(c) && !(c >= '0' && c <= '9') {
+			for buf.index < buf.length && identifier(buf.data[buf.index]) {
+				buf.index++
+			}
+			key = string(buf.data[start:buf.index])
+			buf.index--
+			p.current.mark()
+		} else {
+			return false, nil
+		}
+		p.key, p.keyIndex = &key, start
+		buf.state = CO
+		return true, nil
+	case GO, VA, AR:
+		if c == bracketR && buf.state == VA && p.current.IsArray() { // trailing comma
+			buf.state = OK
+			p.current.mark()
+			return false, nil
+		}
+		var (
+			value  interface{}
+			_type  NodeType
+			strict bool
+			start  = buf.index
+		)
+		if c == quotes || c == quote {
+			_type = String
+			value, err = p.string(c)
+			strict = c == quotes && strictString(buf.data[start:buf.index+1])
+		} else if c == plus || c == minus || c == dot || (c >= '0' && c <= '9') || c == 'I' || c == 'N' {
+			_type = Numeric
+			value, err = p.numeric()
+			strict = strictNumeric(buf.data[start : buf.index+1])
+		} else {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		buf.index, start = start, buf.index
+		p.current, err = p.newNode(_type)
+		if err != nil {
+			return false, err
+		}
+		buf.index = start
+		p.current.borders[1] = buf.index + 1
+		if !strict {
+			p.current.value.Store(value)
+			p.current.mark()
+		}
+		buf.state = OK
+		if p.current.parent != nil {
+			p.current = p.current.parent
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// string reads the string token in single or double quotes, the index will point to the closing quote
+func (p *parser) string(border byte) (value string, err error) {
+	buf := p.buf
+	start := buf.index
+	for buf.index++; buf.index < buf.length; buf.index++ {
+		switch buf.data[buf.index] {
+		case backslash:
+			buf.index++
+			if buf.index+1 < buf.length && buf.data[buf.index] == skipR && buf.data[buf.index+1] == skipN {
+				buf.index++
+			}
+		case border:
+			if err = p.limitString(buf.index-start-1, start); err != nil {
+				return "", err
+			}
+			value, ok := unquoteRelaxed(buf.data[start:buf.index+1], border)
+			if !ok {
+				return "", errorAt(start, border)
+			}
+			return value, nil
+		case skipN, skipR:
+			return "", buf.errorSymbol()
+		}
+	}
+	return "", buf.errorEOF()
+}
+
+// numeric reads the JSON5 numeric token, the index will point to the last symbol of it
+func (p *parser) numeric() (value float64, err error) {
+	buf := p.buf
+	start := buf.index
+	sign := 1.0
+	if c := buf.data[buf.index]; c == plus || c == minus {
+		if c == minus {
+			sign = -1
+		}
+		buf.index++
+	}
+	data := buf.data[buf.index:]
+	switch {
+	case bytes.HasPrefix(data, _infinity):
+		buf.index += len(_infinity) - 1
+		return math.Inf(int(sign)), nil
+	case bytes.HasPrefix(data, _nan):
+		buf.index += len(_nan) - 1
+		return math.NaN(), nil
+	case len(data) > 1 && data[0] == '0' && (data[1] == 'x' || data[1] == 'X'):
+		buf.index += 2
+		from := buf.index
+		for buf.index < buf.length && isHex(buf.data[buf.index]) {
+			buf.index++
+		}
+		if from == buf.index {
+			return 0, buf.errorSymbol()
+		}
+		integer, err := strconv.ParseUint(string(buf.data[from:buf.index]), 16, 64)
+		buf.index--
+		if err != nil {
+			return 0, errorAt(start, buf.data[start])
+		}
+		return sign * float64(integer), nil
+	}
+
+	if len(data) > 1 && data[0] == '0' && data[1] >= '0' && data[1] <= '9' {
+		buf.index++
+		return 0, buf.errorSymbol()
+	}
+	digits := 0
+	for ; buf.index < buf.length; buf.index++ {
+		c := buf.data[buf.index]
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c != dot {
+			break
+		}
+	}
+	if digits == 0 {
+		return 0, buf.errorSymbol()
+	}
+	if buf.index < buf.length && (buf.data[buf.index] == 'e' || buf.data[buf.index] == 'E') {
+		buf.index++
+		if buf.index < buf.length && (buf.data[buf.index] == plus || buf.data[buf.index] == minus) {
+			buf.index++
+		}
+		from := buf.index
+		for buf.index < buf.length && buf.data[buf.index] >= '0' && buf.data[buf.index] <= '9' {
+			buf.index++
+		}
+		if from == buf.index {
+			if buf.index < buf.length {
+				return 0, buf.errorSymbol()
+			}
+			return 0, buf.errorEOF()
+		}
+	}
+	token := string(buf.data[start:buf.index])
+	buf.index--
+	value, err = strconv.ParseFloat(token, 64)
+	if err != nil {
+		if num, ok := err.(*strconv.NumError); ok && num.Err == strconv.ErrRange {
+			return value, nil
+		}
+		return 0, errorAt(start, buf.data[start])
+	}
+	return value, nil
+}
+
+// relaxedSpace returns the size of the whitespace at the beginning of data, and if it's a valid JSON whitespace
+func relaxedSpace(data []byte) (strict bool, size int) {
+	switch data[0] {
+	case skipS, skipN, skipR, skipT:
+		return true, 1
+	case '\v', '\f':
+		return false, 1
+	}
+	for _, space := range _spaces {
+		if bytes.HasPrefix(data, space) {
+			return false, len(space)
+		}
+	}
+	return false, 0
+}
+
+// strictString checks if the string token is a valid JSON string
+func strictString(data []byte) bool {
+	buf := newBuffer(data)
+	return buf.string(quotes, true) == nil && buf.index == len(data)-1
+}
+
+// strictNumeric checks if the numeric token is a valid JSON number
+func strictNumeric(data []byte) bool {
+	buf := newBuffer(data)
+	return buf.numeric(true) == nil && buf.index == len(data)
+}
+
+func identifier(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == dollar || c >= utf8.RuneSelf
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// unquoteRelaxed converts a quoted JSON5 string literal s into an actual string t.
+func unquoteRelaxed(s []byte, border byte) (t string, ok bool) {
+	if len(s) < 2 || s[0] != border || s[len(s)-1] != border {
+		return
+	}
+	s = s[1 : len(s)-1]
+	b := make([]byte, 0, len(s))
+	for r := 0; r < len(s); r++ {
+		c := s[r]
+		if c == border || c == skipN || c == skipR {
+			return
+		}
+		if c != backslash {
+			b = append(b, c)
+			continue
+		}
+		r++
+		if r >= len(s) {
+			return
+		}
+		switch c = s[r]; c {
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '0':
+			if r+1 < len(s) && s[r+1] >= '0' && s[r+1] <= '9' {
+				return
+			}
+			b = append(b, 0)
+		case 'x':
+			if r+2 >= len(s) || !isHex(s[r+1]) || !isHex(s[r+2]) {
+				return
+			}
+			value, _ := strconv.ParseUint(string(s[r+1:r+3]), 16, 8)
+			b = append(b, string(rune(value))...)
+			r += 2
+		case 'u':
+			rr := getu4(s[r-1:])
+			if rr < 0 {
+				return
+			}
+			r += 4
+			if utf16.IsSurrogate(rr) {
+				if dec := utf16.DecodeRune(rr, getu4(s[r+1:])); dec != unicode.ReplacementChar {
+					rr = dec
+					r += 6
+				} else {
+					rr = unicode.ReplacementChar
+				}
+			}
+			b = append(b, string(rr)...)
+		case skipN: // line continuation
+		case skipR:
+			if r+1 < len(s) && s[r+1] == skipN {
+				r++
+			}
+		default:
+			if c >= '1' && c <= '9' {
+				return
+			}
+			if bytes.HasPrefix(s[r:], _spaces[2]) || bytes.HasPrefix(s[r:], _spaces[3]) {
+				r += 2 // line continuation: LS, PS
+				continue
+			}
+			b = append(b, c)
+		}
+	}
+	return string(b), true
+}
@@ -0,0 +1,270 @@
+package ajson
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestUnmarshalWithOptions_relaxed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  interface{}
+	}{
+		{name: "line comment", input: "// comment\n[1, // one\n2]// end", want: []interface{}{float64(1), float64(2)}},
+		{name: "block comment", input: "/* comment */[1, /* two\n */2]/**/", want: []interface{}{float64(1), float64(2)}},
+		{name: "comment in object", input: `{"a" /* key */ : /* value */ 1}`, want: map[string]interface{}{"a": float64(1)}},
+		{name: "trailing comma in array", input: `[1, 2, ]`, want: []interface{}{float64(1), float64(2)}},
+		{name: "trailing comma in object", input: `{"a": 1, }`, want: map[string]interface{}{"a": float64(1)}},
+		{name: "single quoted string", input: `['foo', 'b"a\'r']`, want: []interface{}{"foo", `b"a'r`}},
+		{name: "single quoted key", input: `{'foo': 1}`, want: map[string]interface{}{"foo": float64(1)}},
+		{name: "unquoted keys", input: `{foo: 1, _bar$2: 2, $: 3}`, want: map[string]interface{}{"foo": float64(1), "_bar$2": float64(2), "$": float64(3)}},
+		{name: "unicode key", input: `{ключ: 1}`, want: map[string]interface{}{"ключ": float64(1)}},
+		{name: "escapes", input: `"\x41\v\0\'\"\Aß"`, want: "A\v\x00'\"Aß"},
+		{name: "line continuation", input: "'foo\\\nbar\\\r\nbaz'", want: "foobarbaz"},
+		{name: "hexadecimal", input: `[0x1F, -0XfF, +0x0]`, want: []interface{}{float64(31), float64(-255), float64(0)}},
+		{name: "decimals", input: `[.5, 5., +1, -.5e1, 1e2]`, want: []interface{}{0.5, float64(5), float64(1), -5.0, float64(100)}},
+		{name: "infinity", input: `[Infinity, -Infinity, +Infinity]`, want: []interface{}{math.Inf(1), math.Inf(-1), math.Inf(1)}},
+		{name: "whitespaces", input: "\ufeff[\v1,\f2\u00a0, 3]", want: []interface{}{float64(1), float64(2), float64(3)}},
+		{name: "strict", input: `{"a": [1, "b", true, null]}`, want: map[string]interface{}{"a": []interface{}{float64(1), "b", true, nil}}},
+		{
+			name: "config",
+			input: `// configuration
+{
+  name: 'server',
+  port: 0x1F90,
+  hosts: [
+    "a.example.com",
+    'b.example.com', // backup
+  ],
+  /* limits */
+  limits: {ratio: .75, max: +Infinity,},
+}`,
+			want: map[string]interface{}{
+				"name":   "server",
+				"port":   float64(8080),
+				"hosts":  []interface{}{"a.example.com", "b.example.com"},
+				"limits": map[string]interface{}{"ratio": 0.75, "max": math.Inf(1)},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := UnmarshalWithOptions([]byte(test.input), Options{Relaxed: true})
+			if err != nil {
+				t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+			}
+			value, err := root.Unpack()
+			if err != nil {
+				t.Fatalf("Unpack() unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(value, test.want) {
+				t.Errorf("UnmarshalWithOptions() wrong value: %#v, expected: %#v", value, test.want)
+			}
+			if _, err = Unmarshal([]byte(test.input)); err == nil && test.name != "strict" {
+				t.Errorf("Unmarshal() expected error")
+			}
+		})
+	}
+}
+
+func TestNewDecoderWithOptions_relaxed(t *testing.T) {
+	tests := []string{
+		"// comment\n[1, /* two */ 2]// end",
+		`['foo', 'b"a\'r', "baz"]`,
+		`{'foo': 'bar', baz: 'qux',}`,
+		`[0x1F, .5, +Infinity, -1e2]`,
+		"'foo\\\nbar'",
+		"[" + strings.Repeat(`'abcdefghij', `, 4000) + "]",
+	}
+	readers := map[string]func(io.Reader) io.Reader{
+		"full":     func(r io.Reader) io.Reader { return r },
+		"one byte": iotest.OneByteReader,
+		"half":     iotest.HalfReader,
+	}
+	for i, test := range tests {
+		expected, err := UnmarshalWithOptions([]byte(test), Options{Relaxed: true})
+		if err != nil {
+			t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+		}
+		for name, reader := range readers {
+			t.Run(fmt.Sprintf("%d/%s", i, name), func(t *testing.T) {
+				decoder := NewDecoderWithOptions(reader(strings.NewReader(test)), Options{Relaxed: true})
+				root, err := decoder.Decode()
+				if err != nil {
+					t.Fatalf("Decode() unexpected error: %s", err)
+				}
+				if !equal(root, expected) {
+					t.Errorf("Decode() wrong result: %s", root)
+				}
+				if _, err = decoder.Decode(); err != io.EOF {
+					t.Errorf("Decode() expected EOF, got: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestNewParserWithOptions_relaxed(t *testing.T) {
+	parser := NewParserWithOptions(Options{Relaxed: true})
+	for _, chunk := range []string{`['ab`, `c', 'd\`, `'e'`, `]`} {
+		if _, err := parser.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write(%s) unexpected error: %s", chunk, err)
+		}
+	}
+	if err := parser.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %s", err)
+	}
+	root := parser.Root()
+	if result := root.MustIndex(0).MustString() + root.MustIndex(1).MustString(); result != "abcd'e" {
+		t.Errorf("wrong result: %s", result)
+	}
+}
+
+func TestNewParserWithOptions_relaxed_keys(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		key    string
+	}{
+		{name: "underscore", chunks: []string{`{foo_`, `bar: 1}`}, key: "foo_bar"},
+		{name: "dollar", chunks: []string{`{$a`, `$b: 1}`}, key: "$a$b"},
+		{name: "unicode", chunks: []string{`{клю`, `ч: 1}`}, key: "ключ"},
+		{name: "split rune", chunks: []string{"{\xd0", "\xba: 1}"}, key: "к"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := NewParserWithOptions(Options{Relaxed: true})
+			for _, chunk := range test.chunks {
+				if _, err := parser.Write([]byte(chunk)); err != nil {
+					t.Fatalf("Write(%s) unexpected error: %s", chunk, err)
+				}
+			}
+			if err := parser.Close(); err != nil {
+				t.Fatalf("Close() unexpected error: %s", err)
+			}
+			if value, err := parser.Root().GetKey(test.key); err != nil || value.MustNumeric() != 1 {
+				t.Errorf("wrong result: %s", parser.Root())
+			}
+		})
+	}
+}
+
+func TestUnmarshalWithOptions_relaxed_NaN(t *testing.T) {
+	root, err := UnmarshalWithOptions([]byte(`[NaN, -NaN]`), Options{Relaxed: true})
+	if err != nil {
+		t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+	for _, node := range root.MustArray() {
+		if !math.IsNaN(node.MustNumeric()) {
+			t.Errorf("wrong value: %v", node.MustNumeric())
+		}
+	}
+	if _, err = Marshal(root); err == nil {
+		t.Errorf("Marshal() expected error")
+	}
+	if _, err = Marshal(NumericNode("", math.Inf(-1))); err == nil || err.(Error).Type != WrongRequest {
+		t.Errorf("Marshal() expected WrongRequest error, got: %v", err)
+	}
+}
+
+func TestUnmarshalWithOptions_relaxed_error(t *testing.T) {
+	tests := []string{
+		``,
+		`/* unclosed`,
+		`[1 /`,
+		`[1] /`,
+		`[1,,]`,
+		`[,]`,
+		`{,}`,
+		`{a b: 1}`,
+		`{1a: 1}`,
+		`{"a": 1,,}`,
+		`'unclosed`,
+		`'new
+line'`,
+		`'\1'`,
+		`'\x4'`,
+		`0x`,
+		`0xZ`,
+		`01`,
+		`1.2.3`,
+		`1e`,
+		`.`,
+		`+`,
+		`Infinit`,
+		`Infinityx`,
+		`[1] // comment
+2`,
+	}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			if root, err := UnmarshalWithOptions([]byte(test), Options{Relaxed: true}); err == nil {
+				t.Errorf("UnmarshalWithOptions() expected error, got: %s", root)
+			}
+		})
+	}
+}
+
+func TestUnmarshalWithOptions_relaxed_source(t *testing.T) {
+	root, err := UnmarshalWithOptions([]byte(`{
+		strict: {"a": [1, 2.5e1, "b"]},
+		relaxed: ['a', 0x10, /* comment */ 3,],
+	}`), Options{Relaxed: true})
+	if err != nil {
+		t.Fatalf("UnmarshalWithOptions() unexpected error: %s", err)
+	}
+	strict := root.MustKey("strict")
+	if strict.IsDirty() || string(strict.Source()) != `{"a": [1, 2.5e1, "b"]}` {
+		t.Errorf("strict node is corrupted: %s", strict.Source())
+	}
+	relaxed := root.MustKey("relaxed")
+	if !relaxed.IsDirty() || relaxed.Source() != nil {
+		t.Errorf("relaxed node expected to be dirty")
+	}
+	if !relaxed.MustIndex(0).IsDirty() || !relaxed.MustIndex(1).IsDirty() || relaxed.MustIndex(2).IsDirty() {
+		t.Errorf("relaxed values are corrupted")
+	}
+	if !root.IsDirty() {
+		t.Errorf("root node expected to be dirty")
+	}
+
+	result, err := Marshal(relaxed)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %s", err)
+	}
+	if string(result) != `["a",16,3]` {
+		t.Errorf("Marshal() wrong result: %s", result)
+	}
+	result, err = Marshal(root)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %s", err)
+	}
+	if _, err = Unmarshal(result); err != nil {
+		t.Errorf("Marshal() result is not a valid JSON: %s", result)
+	}
+}
+
+func ExampleUnmarshalWithOptions() {
+	data := []byte(`{
+		// JSON5 config
+		name: 'example',
+		ports: [0x50, 443,],
+	}`)
+	root, err := UnmarshalWithOptions(data, Options{Relaxed: true})
+	if err != nil {
+		panic(err)
+	}
+	result, err := Marshal(root.MustKey("ports"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s: %s", root.MustKey("name").MustString(), result)
+	// Output:
+	// example: [80,443]
+}
@@ -0,0 +1,99 @@
+package ajson
+
+import (
+	"database/sql/driver"
+	"sync/atomic"
+)
+
+// This file contains implementations of the standard library interfaces: json.Marshaler, json.Unmarshaler and
+// sql.Scanner. Node can't implement driver.Valuer, because of the Node.Value method, use Node.Valuer instead.
+
+// MarshalJSON implements json.Marshaler interface, result is the same as Marshal returns.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	if n == nil {
+		return []byte(_null), nil
+	}
+	return Marshal(n)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface: node gets the parsed value of the data, the same as Unmarshal
+// returns. If the node has the parent, it will keep its place in the tree.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	if n == nil {
+		return errorUnparsed()
+	}
+	root, err := Unmarshal(append([]byte(nil), data...))
+	if err != nil {
+		return err
+	}
+	return n.assign(root)
+}
+
+// Scan implements sql.Scanner interface: node gets the parsed value of the JSON column, given as []byte or string.
+// SQL NULL is scanned as Null node.
+func (n *Node) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		if n == nil {
+			return errorUnparsed()
+		}
+		return n.assign(NullNode(""))
+	case []byte:
+		return n.UnmarshalJSON(value)
+	case string:
+		return n.UnmarshalJSON([]byte(value))
+	}
+	return unsupportedType(src)
+}
+
+// Valuer returns driver.Valuer of the node, so it can be stored into the JSON column with database/sql:
+//
+// 	_, err := db.Exec("INSERT INTO documents (body) VALUES ($1)", node.Valuer())
+//
+// Value of it is the marshaled node, nil node and Null node are stored as SQL NULL.
+func (n *Node) Valuer() driver.Valuer {
+	return valuer{node: n}
+}
+
+type valuer struct {
+	node *Node
+}
+
+// Value implements driver.Valuer interface
+func (v valuer) Value() (driver.Value, error) {
+	if v.node == nil || v.node.IsNull() {
+		return nil, nil
+	}
+	return Marshal(v.node)
+}
+
+// assign replaces the value of the node with the value of the given root node, keeping the place of the node in the
+// tree
+func (n *Node) assign(root *Node) error {
+	after, err := n.emit(func() Event {
+		return Event{Type: EventSet, Node: n, Path: n.Path(), Old: n.Clone(), New: root.Clone()}
+	})
+	if err != nil {
+		return err
+	}
+	defer after()
+	defer n.record()()
+	if n.parent != nil {
+		n.parent.mark()
+	}
+	n.clear()
+	atomic.StoreInt32((*int32)(&n._type), int32(root._type))
+	n.data = root.data
+	n.origin = root.origin
+	n.borders = root.borders
+	n.children = root.children
+	n.order = root.order
+	n.duplicates = root.duplicates
+	n.value = root.value
+	n.dirty = root.dirty
+	n.constructed = root.constructed
+	for _, child := range n.children {
+		child.parent = n
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package ajson
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestNode_MarshalJSON(t *testing.T) {
+	type document struct {
+		ID   int   `json:"id"`
+		Body *Node `json:"body"`
+		Null *Node `json:"null"`
+	}
+	body := Must(Unmarshal([]byte(`{"a": [1, 2]}`)))
+	if err := body.MustKey("a").AppendArray(NumericNode("", 3)); err != nil {
+		t.Fatalf("AppendArray() unexpected error: %s", err)
+	}
+	result, err := json.Marshal(document{ID: 1, Body: body})
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error: %s", err)
+	}
+	if string(result) != `{"id":1,"body":{"a":[1,2,3]},"null":null}` {
+		t.Errorf("json.Marshal() wrong result: %s", result)
+	}
+
+	var decoded document
+	if err = json.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error: %s", err)
+	}
+	if decoded.ID != 1 || decoded.Body == nil || decoded.Null != nil {
+		t.Fatalf("json.Unmarshal() wrong result: %#v", decoded)
+	}
+	if value := decoded.Body.MustKey("a").MustIndex(2).MustNumeric(); value != 3 {
+		t.Errorf("json.Unmarshal() wrong value: %v", value)
+	}
+	if string(decoded.Body.Source()) != `{"a":[1,2,3]}` {
+		t.Errorf("json.Unmarshal() wrong source: %s", decoded.Body.Source())
+	}
+	if decoded.Body.MustKey("a").Parent() != decoded.Body {
+		t.Errorf("json.Unmarshal() wrong parent")
+	}
+}
+
+func TestNode_UnmarshalJSON_child(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": 1}, "c": 2}`)))
+	child := root.MustKey("a")
+	if err := child.UnmarshalJSON([]byte(`[true]`)); err != nil {
+		t.Fatalf("UnmarshalJSON() unexpected error: %s", err)
+	}
+	if root.MustKey("a") != child || !child.IsArray() || child.Key() != "a" {
+		t.Errorf("UnmarshalJSON() wrong child")
+	}
+	result, err := Marshal(root)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %s", err)
+	}
+	if string(result) != `{"a":[true],"c":2}` {
+		t.Errorf("Marshal() wrong result: %s", result)
+	}
+	if err = child.UnmarshalJSON([]byte(`[`)); err == nil {
+		t.Errorf("UnmarshalJSON() expected error")
+	}
+	if err = (*Node)(nil).UnmarshalJSON([]byte(`1`)); err == nil {
+		t.Errorf("(*Node)(nil).UnmarshalJSON() expected error")
+	}
+}
+
+func TestNode_Scan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      interface{}
+		expected string
+		err      bool
+	}{
+		{name: "bytes", src: []byte(`{"a":1}`), expected: `{"a":1}`},
+		{name: "string", src: `[1,2]`, expected: `[1,2]`},
+		{name: "nil", src: nil, expected: `null`},
+		{name: "wrong type", src: 1, err: true},
+		{name: "wrong json", src: `{`, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := new(Node)
+			err := node.Scan(test.src)
+			if test.err {
+				if err == nil {
+					t.Errorf("Scan() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan() unexpected error: %s", err)
+			}
+			result, err := Marshal(node)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %s", err)
+			}
+			if string(result) != test.expected {
+				t.Errorf("Scan() wrong result: %s", result)
+			}
+		})
+	}
+}
+
+// fakeDriver is a database/sql driver, that stores the only value of the single column in the memory
+type fakeDriver struct {
+	value driver.Value
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{driver: d}, nil }
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+func (s *fakeStmt) Close() error              { return nil }
+func (s *fakeStmt) NumInput() int {
+	if s.query == "insert" {
+		return 1
+	}
+	return 0
+}
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.driver.value = args[0]
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{value: s.conn.driver.value}, nil
+}
+func (r *fakeRows) Columns() []string { return []string{"body"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestNode_Valuer(t *testing.T) {
+	fake := new(fakeDriver)
+	sql.Register("ajson_fake", fake)
+	db, err := sql.Open("ajson_fake", "")
+	if err != nil {
+		t.Fatalf("sql.Open() unexpected error: %s", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	tests := []struct {
+		name     string
+		node     *Node
+		stored   driver.Value
+		expected string
+	}{
+		{name: "object", node: Must(Unmarshal([]byte(`{"a": [1, "b"]}`))), stored: []byte(`{"a": [1, "b"]}`), expected: `{"a": [1, "b"]}`},
+		{name: "changed", node: ArrayNode("", []*Node{BoolNode("", true)}), stored: []byte(`[true]`), expected: `[true]`},
+		{name: "null", node: NullNode(""), stored: nil, expected: `null`},
+		{name: "nil", node: nil, stored: nil, expected: `null`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := db.Exec("insert", test.node.Valuer()); err != nil {
+				t.Fatalf("Exec() unexpected error: %s", err)
+			}
+			if stored, ok := fake.value.([]byte); (ok && string(stored) != string(test.stored.([]byte))) || (!ok && test.stored != nil) {
+				t.Errorf("Value() wrong result: %#v", fake.value)
+			}
+			result := new(Node)
+			if err := db.QueryRow("select").Scan(result); err != nil {
+				t.Fatalf("Scan() unexpected error: %s", err)
+			}
+			if result.String() != test.expected {
+				t.Errorf("Scan() wrong result: %s", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package ajson
+
+import (
+	"sync/atomic"
+)
+
+// Transaction is the journal of the mutations of the document. It is attached to the root node by Node.Begin and
+// records every change of the tree, made by the mutation methods (Node.SetNode, Node.AppendObject, Node.DeleteKey
+// and others), so they can be undone and redone, or rolled back all together.
+//
+// Journal keeps only the previous state of the changed nodes, the references of their direct children and the dirty
+// flags of their parents, the tree is never cloned. Every call of the mutation method is the single step of the
+// history, use Transaction.Batch to join several calls into one step.
+//
+// Transaction is not safe for concurrent use, like the Node itself.
+type Transaction struct {
+	root   *Node
+	done   [][]*record
+	undone [][]*record
+	step   []*record
+	depth  int
+	// reported is true if any observer was notified about the changes of the current batch
+	reported bool
+}
+
+// record is the state of the node before the change, with the references of its children and related nodes, and
+// the dirty flags of its parents
+type record struct {
+	node    *Node
+	state   Node
+	value   interface{}
+	refs    []reference
+	parents []flag
+}
+
+// flag is the dirty flag of the node
+type flag struct {
+	node  *Node
+	dirty bool
+}
+
+// reference is the place of the node in the tree
+type reference struct {
+	node   *Node
+	parent *Node
+	key    *string
+	index  *int
+}
+
+// Begin starts the transaction for the document of the current root node.
+//
+// Example:
+//
+// 	tx, err := root.Begin()
+// 	if err != nil {
+// 		return err
+// 	}
+// 	if err = root.MustKey("name").SetString("new"); err != nil {
+// 		return tx.Rollback()
+// 	}
+// 	return tx.Commit()
+func (n *Node) Begin() (*Transaction, error) {
+	if n == nil {
+		return nil, errorUnparsed()
+	}
+	if n.parent != nil {
+		return nil, errorRequest("transaction can be started only for the root node")
+	}
+	if n.transaction != nil {
+		return nil, errorRequest("transaction is already started")
+	}
+	n.transaction = &Transaction{root: n}
+	return n.transaction, nil
+}
+
+// Commit finishes the transaction and keeps all the changes, history of the changes is dropped.
+func (t *Transaction) Commit() error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	t.finish()
+	return nil
+}
+
+// Rollback finishes the transaction and reverts all the changes, made after Node.Begin.
+func (t *Transaction) Rollback() error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	for len(t.done) > 0 {
+		t.revert(true)
+	}
+	t.finish()
+	return nil
+}
+
+// Undo reverts the last step of the changes, it can be redone with Redo.
+func (t *Transaction) Undo() error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	if len(t.done) == 0 {
+		return errorRequest("nothing to undo")
+	}
+	t.undone = append(t.undone, t.revert(true))
+	return nil
+}
+
+// Redo makes the last undone step of the changes again. Any new change drops the steps, that can be redone.
+func (t *Transaction) Redo() error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	if len(t.undone) == 0 {
+		return errorRequest("nothing to redo")
+	}
+	last := len(t.undone) - 1
+	t.done = append(t.done, restore(t.undone[last], true))
+	t.undone = t.undone[:last]
+	return nil
+}
+
+// CanUndo returns true if there is the step, that can be undone.
+func (t *Transaction) CanUndo() bool {
+	return len(t.done) > 0
+}
+
+// CanRedo returns true if there is the step, that can be redone.
+func (t *Transaction) CanRedo() bool {
+	return len(t.undone) > 0
+}
+
+// Batch calls the function and records all its changes as the single step of the history. If the function returns
+// error, its changes are reverted; the revert is reported to the observers only if they were notified about the
+// changes.
+func (t *Transaction) Batch(fn func() error) error {
+	if err := t.check(); err != nil {
+		return err
+	}
+	if t.depth > 0 {
+		return fn()
+	}
+	reported := t.reported
+	t.reported = false
+	t.depth++
+	err := fn()
+	t.depth--
+	changed := t.close()
+	if err != nil && changed {
+		t.revert(t.reported)
+	}
+	t.reported = reported || t.reported
+	return err
+}
+
+// check returns error if the transaction is finished or the step is not closed
+func (t *Transaction) check() error {
+	if t == nil || t.root == nil || t.root.transaction != t {
+		return errorRequest("transaction is finished")
+	}
+	if t.depth > 0 {
+		return errorRequest("transaction is in the middle of the change")
+	}
+	return nil
+}
+
+// finish detaches the transaction from the root node
+func (t *Transaction) finish() {
+	t.root.transaction = nil
+	t.done, t.undone, t.step = nil, nil, nil
+}
+
+// revert reverts the last step of the changes and returns the records to redo it
+func (t *Transaction) revert(notify bool) []*record {
+	last := len(t.done) - 1
+	step := restore(t.done[last], notify)
+	t.done = t.done[:last]
+	return step
+}
+
+// close adds the current step to the history and returns true if it has any records
+func (t *Transaction) close() bool {
+	if len(t.step) == 0 {
+		return false
+	}
+	t.done = append(t.done, t.step)
+	t.step = nil
+	t.undone = nil
+	return true
+}
+
+// record saves the state of the node into the transaction of its document before the change, returned function must
+// be called after the change. The related nodes are the nodes, which references are changed, but they are not the
+// children of the node before the change.
+func (n *Node) record(related ...*Node) func() {
+	t := n.root().transaction
+	if t == nil {
+		return func() {}
+	}
+	t.step = append(t.step, capture(n, related))
+	t.depth++
+	return func() {
+		t.depth--
+		if t.depth == 0 {
+			t.close()
+		}
+	}
+}
+
+// capture returns the current state of the node
+func capture(node *Node, related []*Node) *record {
+	result := &record{
+		node:  node,
+		state: *node,
+		value: node.value.Load(),
+		refs:  make([]reference, 0, len(node.children)+len(related)),
+	}
+	result.state.value = atomic.Value{}
+	if node.children != nil {
+		result.state.children = make(map[string]*Node, len(node.children))
+		for key, child := range node.children {
+			result.state.children[key] = child
+			result.refs = append(result.refs, reference{node: child, parent: child.parent, key: child.key, index: child.index})
+		}
+	}
+	if node.order != nil {
+		result.state.order = make([]string, len(node.order))
+		copy(result.state.order, node.order)
+	}
+	if node.duplicates != nil {
+		result.state.duplicates = make(map[string][]*Node, len(node.duplicates))
+		for key, duplicates := range node.duplicates {
+			result.state.duplicates[key] = duplicates
+		}
+	}
+	for _, current := range related {
+		result.refs = append(result.refs, reference{node: current, parent: current.parent, key: current.key, index: current.index})
+	}
+	for current := node.parent; current != nil; current = current.parent {
+		result.parents = append(result.parents, flag{node: current, dirty: current.dirty})
+	}
+	return result
+}
+
+// restore sets the recorded states of the nodes in the reverse order, notifies their observers, if required, and
+// returns the records of their current states
+func restore(step []*record, notify bool) []*record {
+	result := make([]*record, 0, len(step))
+	for i := len(step) - 1; i >= 0; i-- {
+		current := step[i]
+		related := make([]*Node, 0, len(current.refs))
+		for _, ref := range current.refs {
+			related = append(related, ref.node)
+		}
+		result = append(result, capture(current.node, related))
+
+		observers := current.node.observers
+		*current.node = current.state
+		current.node.observers = observers
+		if current.value != nil {
+			current.node.value.Store(current.value)
+		}
+		for _, ref := range current.refs {
+			ref.node.parent, ref.node.key, ref.node.index = ref.parent, ref.key, ref.index
+		}
+		for _, parent := range current.parents {
+			parent.node.dirty = parent.dirty
+		}
+	}
+	if !notify {
+		return result
+	}
+	notified := make(map[*Node]bool, len(step))
+	for i := len(step) - 1; i >= 0; i-- {
+		if node := step[i].node; !notified[node] {
+			notified[node] = true
+			node.notify()
+		}
+	}
+	return result
+}
+
+// atomically calls the function and reverts all its changes of the document, if it returns error: with the step of
+// the current transaction, or with the temporary one, if the transaction is not started.
+func (n *Node) atomically(fn func() error) error {
+	root := n.root()
+	if root.transaction != nil {
+		if root.transaction.depth > 0 {
+			// the change is the part of the outer step, which is reverted by its owner
+			return fn()
+		}
+		return root.transaction.Batch(fn)
+	}
+	t, err := root.Begin()
+	if err != nil {
+		return err
+	}
+	if err = fn(); err != nil {
+		for len(t.done) > 0 {
+			t.revert(t.reported)
+		}
+		t.finish()
+		return err
+	}
+	return t.Commit()
+}
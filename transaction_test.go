@@ -0,0 +1,213 @@
+package ajson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTransaction_Rollback(t *testing.T) {
+	source := `{"a": [1, 2, 3], "b": {"c": "d", "e": null}, "f": true}`
+	root := Must(Unmarshal([]byte(source)))
+	array, object := root.MustKey("a"), root.MustKey("b")
+	tx, err := root.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	mutations := []func() error{
+		func() error { return array.MustIndex(0).SetNumeric(10) },
+		func() error { return array.AppendArray(StringNode("", "x"), object.MustKey("e")) },
+		func() error { return array.InsertIndex(1, NullNode(""), array.MustIndex(-1)) },
+		func() error { return array.DeleteIndex(0) },
+		func() error { return object.RenameKey("c", "g") },
+		func() error { return root.Swap("a", "f") },
+		func() error { return object.MustKey("g").MoveTo(array, 2) },
+		func() error { return object.SetObject(map[string]*Node{"h": NumericNode("", 1)}) },
+		func() error { return root.MustKey("f").SetNode(Must(Unmarshal([]byte(`{"i": [1]}`)))) },
+		func() error { return root.MustKey("f").MustKey("i").UnmarshalJSON([]byte(`{"j": 2}`)) },
+		func() error {
+			return ApplyPatch(root, Must(Unmarshal([]byte(`[{"op": "move", "from": "/a/0", "path": "/k"}]`))))
+		},
+		func() error { return MergePatch(root, Must(Unmarshal([]byte(`{"b": null, "l": {"m": 1}}`)))) },
+		func() error { return root.SetNode(ArrayNode("", nil)) },
+	}
+	for i, mutation := range mutations {
+		if err = mutation(); err != nil {
+			t.Fatalf("mutation %d unexpected error: %s", i, err)
+		}
+		checkReferences(t, root)
+	}
+	if err = tx.Rollback(); err != nil {
+		t.Fatalf("Rollback() unexpected error: %s", err)
+	}
+	if root.String() != source || root.IsDirty() {
+		t.Errorf("Rollback() wrong result: %s", root)
+	}
+	if root.MustKey("a") != array || root.MustKey("b") != object || array.MustIndex(0).Parent() != array {
+		t.Errorf("Rollback() wrong nodes")
+	}
+	if value := array.MustArray(); len(value) != 3 || value[2].MustNumeric() != 3 {
+		t.Errorf("Rollback() wrong value: %v", value)
+	}
+	checkReferences(t, root)
+	if err = tx.Undo(); err == nil {
+		t.Errorf("Undo() expected error for the finished transaction")
+	}
+}
+
+func TestTransaction_Undo(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, 2], "b": "c"}`)))
+	tx, err := root.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	states := []string{root.String()}
+	steps := []func() error{
+		func() error { return root.MustKey("b").SetString("d") },
+		func() error { return root.MustKey("a").AppendArray(NumericNode("", 3), NumericNode("", 4)) },
+		func() error { return root.DeleteKey("b") },
+		func() error { return root.AppendObject("e", root.MustKey("a").MustIndex(0)) },
+	}
+	for _, step := range steps {
+		if err = step(); err != nil {
+			t.Fatalf("step unexpected error: %s", err)
+		}
+		result, _ := Marshal(root)
+		states = append(states, string(result))
+	}
+	for i := len(states) - 2; i >= 0; i-- {
+		if err = tx.Undo(); err != nil {
+			t.Fatalf("Undo() unexpected error: %s", err)
+		}
+		if result, _ := Marshal(root); string(result) != states[i] {
+			t.Errorf("Undo() wrong result: %s, expected: %s", result, states[i])
+		}
+		checkReferences(t, root)
+	}
+	if tx.CanUndo() || tx.Undo() == nil {
+		t.Errorf("Undo() expected error, when there is nothing to undo")
+	}
+	for i := 1; i < len(states); i++ {
+		if err = tx.Redo(); err != nil {
+			t.Fatalf("Redo() unexpected error: %s", err)
+		}
+		if result, _ := Marshal(root); string(result) != states[i] {
+			t.Errorf("Redo() wrong result: %s, expected: %s", result, states[i])
+		}
+		checkReferences(t, root)
+	}
+	if tx.CanRedo() || tx.Redo() == nil {
+		t.Errorf("Redo() expected error, when there is nothing to redo")
+	}
+
+	// new change drops the steps to redo
+	if err = tx.Undo(); err != nil {
+		t.Fatalf("Undo() unexpected error: %s", err)
+	}
+	if err = root.MustKey("a").SetNull(); err != nil {
+		t.Fatalf("SetNull() unexpected error: %s", err)
+	}
+	if tx.CanRedo() {
+		t.Errorf("CanRedo() must be false after the new change")
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Commit() unexpected error: %s", err)
+	}
+	if result, _ := Marshal(root); string(result) != `{"a":null}` {
+		t.Errorf("Commit() wrong result: %s", result)
+	}
+	if err = tx.Rollback(); err == nil {
+		t.Errorf("Rollback() expected error for the finished transaction")
+	}
+}
+
+func TestTransaction_Batch(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": 1, "b": 2}`)))
+	tx, err := root.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	err = tx.Batch(func() error {
+		if err := root.MustKey("a").SetNumeric(3); err != nil {
+			return err
+		}
+		return root.DeleteKey("b")
+	})
+	if err != nil {
+		t.Fatalf("Batch() unexpected error: %s", err)
+	}
+	err = tx.Batch(func() error {
+		if err := root.AppendObject("c", NullNode("")); err != nil {
+			return err
+		}
+		if err := tx.Undo(); err == nil {
+			t.Errorf("Undo() expected error inside the batch")
+		}
+		return root.DeleteKey("x")
+	})
+	if err == nil {
+		t.Fatalf("Batch() expected error")
+	}
+	if result, _ := Marshal(root); string(result) != `{"a":3}` {
+		t.Errorf("Batch() wrong result after error: %s", result)
+	}
+	if err = tx.Undo(); err != nil {
+		t.Fatalf("Undo() unexpected error: %s", err)
+	}
+	if root.String() != `{"a": 1, "b": 2}` || tx.CanUndo() {
+		t.Errorf("Undo() must revert the whole batch: %s", root)
+	}
+}
+
+func TestNode_Begin(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": {"b": 1}}`)))
+	if _, err := root.MustKey("a").Begin(); err == nil {
+		t.Errorf("Begin() expected error for not root node")
+	}
+	tx, err := root.Begin()
+	if err != nil {
+		t.Fatalf("Begin() unexpected error: %s", err)
+	}
+	if _, err = root.Begin(); err == nil {
+		t.Errorf("Begin() expected error for the second transaction")
+	}
+
+	// changes of the removed nodes are not recorded
+	child := root.MustKey("a")
+	if err = child.Delete(); err != nil {
+		t.Fatalf("Delete() unexpected error: %s", err)
+	}
+	if err = child.MustKey("b").SetNumeric(2); err != nil {
+		t.Fatalf("SetNumeric() unexpected error: %s", err)
+	}
+	if err = tx.Undo(); err != nil {
+		t.Fatalf("Undo() unexpected error: %s", err)
+	}
+	if tx.CanUndo() || root.MustKey("a") != child {
+		t.Errorf("Undo() wrong result")
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Commit() unexpected error: %s", err)
+	}
+	if _, err = (*Node)(nil).Begin(); err == nil {
+		t.Errorf("Begin() expected error for nil node")
+	}
+}
+
+func ExampleNode_Begin() {
+	root := Must(Unmarshal([]byte(`{"title": "draft", "tags": []}`)))
+	tx, err := root.Begin()
+	if err != nil {
+		panic(err)
+	}
+	_ = root.MustKey("title").SetString("final")
+	_ = root.MustKey("tags").AppendArray(StringNode("", "go"))
+	fmt.Println(root)
+	_ = tx.Undo()
+	fmt.Println(root)
+	_ = tx.Rollback()
+	fmt.Println(root)
+	// Output:
+	// {"title":"final","tags":["go"]}
+	// {"title":"final","tags":[]}
+	// {"title": "draft", "tags": []}
+}
@@ -0,0 +1,78 @@
+package ajson
+
+// WalkAction is the result of the WalkFunc, it defines how walking continues
+type WalkAction int
+
+const (
+	// WalkContinue continues walking as usual
+	WalkContinue WalkAction = iota
+	// WalkSkip skips children of the current node; it has no effect for WalkPost, because children are already visited
+	WalkSkip
+	// WalkStop stops walking, Walk returns without error
+	WalkStop
+	// WalkDelete removes the current node from its parent, children of the removed node are not visited
+	WalkDelete
+)
+
+// WalkFunc is the callback of Walk and WalkPost, depth is 0 for the node given to Walk.
+//
+// Callback can change the current node with the setters (e.g. Node.SetNode), Walk will visit the children of the new
+// value, if it's a container.
+type WalkFunc func(node *Node, depth int) WalkAction
+
+// Walk visits the node and all its descendants in the pre-order: node first, then its children, in the order of
+// Node.Inheritors. Children are taken after the node is visited, so they can be changed by the callback.
+//
+// Error is returned only if the node can't be deleted: the root node, given to Walk, has no parent.
+func Walk(node *Node, fn WalkFunc) error {
+	_, err := walk(node, 0, fn, false)
+	return err
+}
+
+// WalkPost visits the node and all its descendants in the post-order: children first, then the node itself.
+func WalkPost(node *Node, fn WalkFunc) error {
+	_, err := walk(node, 0, fn, true)
+	return err
+}
+
+// walk visits the node and returns false, if walking was stopped
+func walk(node *Node, depth int, fn WalkFunc, post bool) (bool, error) {
+	if node == nil {
+		return true, nil
+	}
+	if !post {
+		switch fn(node, depth) {
+		case WalkStop:
+			return false, nil
+		case WalkSkip:
+			return true, nil
+		case WalkDelete:
+			return true, walkDelete(node)
+		}
+	}
+	for _, child := range node.Inheritors() {
+		if child.parent != node { // removed by the callback
+			continue
+		}
+		next, err := walk(child, depth+1, fn, post)
+		if !next || err != nil {
+			return next, err
+		}
+	}
+	if post {
+		switch fn(node, depth) {
+		case WalkStop:
+			return false, nil
+		case WalkDelete:
+			return true, walkDelete(node)
+		}
+	}
+	return true, nil
+}
+
+func walkDelete(node *Node) error {
+	if node.parent == nil {
+		return errorRequest("node without parent can't be deleted")
+	}
+	return node.parent.remove(node)
+}
@@ -0,0 +1,160 @@
+package ajson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, {"b": 2}], "c": {"d": null}, "e": "f"}`)))
+	tests := []struct {
+		name     string
+		walk     func(*Node, WalkFunc) error
+		action   func(node *Node) WalkAction
+		expected []string
+	}{
+		{
+			name:     "pre-order",
+			walk:     Walk,
+			action:   func(*Node) WalkAction { return WalkContinue },
+			expected: []string{"0:$", "1:$['a']", "2:$['a'][0]", "2:$['a'][1]", "3:$['a'][1]['b']", "1:$['c']", "2:$['c']['d']", "1:$['e']"},
+		},
+		{
+			name:     "post-order",
+			walk:     WalkPost,
+			action:   func(*Node) WalkAction { return WalkContinue },
+			expected: []string{"2:$['a'][0]", "3:$['a'][1]['b']", "2:$['a'][1]", "1:$['a']", "2:$['c']['d']", "1:$['c']", "1:$['e']", "0:$"},
+		},
+		{
+			name: "skip",
+			walk: Walk,
+			action: func(node *Node) WalkAction {
+				if node.IsArray() {
+					return WalkSkip
+				}
+				return WalkContinue
+			},
+			expected: []string{"0:$", "1:$['a']", "1:$['c']", "2:$['c']['d']", "1:$['e']"},
+		},
+		{
+			name: "stop",
+			walk: Walk,
+			action: func(node *Node) WalkAction {
+				if node.IsNull() {
+					return WalkStop
+				}
+				return WalkContinue
+			},
+			expected: []string{"0:$", "1:$['a']", "2:$['a'][0]", "2:$['a'][1]", "3:$['a'][1]['b']", "1:$['c']", "2:$['c']['d']"},
+		},
+		{
+			name: "stop post-order",
+			walk: WalkPost,
+			action: func(node *Node) WalkAction {
+				if node.IsArray() {
+					return WalkStop
+				}
+				return WalkContinue
+			},
+			expected: []string{"2:$['a'][0]", "3:$['a'][1]['b']", "2:$['a'][1]", "1:$['a']"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := make([]string, 0)
+			err := test.walk(root, func(node *Node, depth int) WalkAction {
+				result = append(result, fmt.Sprintf("%d:%s", depth, node.Path()))
+				return test.action(node)
+			})
+			if err != nil {
+				t.Fatalf("Walk() unexpected error: %s", err)
+			}
+			if !sliceEqual(result, test.expected) {
+				t.Errorf("Walk() wrong result:\n%s\nexpected:\n%s", sliceString(result), sliceString(test.expected))
+			}
+		})
+	}
+}
+
+func TestWalk_delete(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": [1, null, 2, null], "b": null, "c": {"d": null, "e": [null]}}`)))
+	visited := 0
+	err := Walk(root, func(node *Node, depth int) WalkAction {
+		visited++
+		if node.IsNull() {
+			return WalkDelete
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"a":[1,2],"c":{"e":[]}}` {
+		t.Errorf("Walk() wrong result: %s", result)
+	}
+	if visited != 11 {
+		t.Errorf("Walk() wrong count of visited nodes: %d", visited)
+	}
+
+	// empty containers are deleted after their children
+	root = Must(Unmarshal([]byte(`{"a": [[], [[]]], "b": {"c": {}}, "d": 1}`)))
+	err = WalkPost(root, func(node *Node, depth int) WalkAction {
+		if depth > 0 && node.isContainer() && node.Size() == 0 {
+			return WalkDelete
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		t.Fatalf("WalkPost() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"d":1}` {
+		t.Errorf("WalkPost() wrong result: %s", result)
+	}
+
+	if err = Walk(root, func(*Node, int) WalkAction { return WalkDelete }); err == nil {
+		t.Errorf("Walk() expected error for the root")
+	}
+}
+
+func TestWalk_replace(t *testing.T) {
+	root := Must(Unmarshal([]byte(`{"a": "x", "b": ["y", {"c": "z"}]}`)))
+	err := Walk(root, func(node *Node, depth int) WalkAction {
+		if node.IsString() {
+			if err := node.SetString(strings.ToUpper(node.MustString())); err != nil {
+				t.Fatalf("SetString() unexpected error: %s", err)
+			}
+		}
+		if node.Key() == "a" {
+			if err := node.SetNode(Must(Unmarshal([]byte(`["w"]`)))); err != nil {
+				t.Fatalf("SetNode() unexpected error: %s", err)
+			}
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		t.Fatalf("Walk() unexpected error: %s", err)
+	}
+	if result := root.String(); result != `{"a":["W"],"b":["Y",{"c":"Z"}]}` {
+		t.Errorf("Walk() wrong result: %s", result)
+	}
+	if err = Walk(nil, func(*Node, int) WalkAction { return WalkContinue }); err != nil {
+		t.Errorf("Walk(nil) unexpected error: %s", err)
+	}
+}
+
+func ExampleWalk() {
+	root := Must(Unmarshal([]byte(`{"name": "root", "password": "secret", "users": [{"name": "user", "password": "123"}]}`)))
+	err := Walk(root, func(node *Node, depth int) WalkAction {
+		if node.Key() == "password" {
+			return WalkDelete
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(root)
+	// Output:
+	// {"name":"root","users":[{"name":"user"}]}
+}